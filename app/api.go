@@ -0,0 +1,313 @@
+package app
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/processor"
+
+	"gocv.io/x/gocv"
+)
+
+// registerAPI mounts the REST/JSON control API under prefix (e.g. "/api"):
+//
+//	GET    /config        current configuration
+//	GET    /steps         pipeline steps with their parameters
+//	PATCH  /steps/{index} merge parameters into one step
+//	PUT    /steps/{index} replace one step
+//	POST   /pipeline      replace the whole pipeline (JSON array of steps)
+//	POST   /pause         stop reading new frames
+//	POST   /resume        continue reading frames
+//	GET    /snapshot      latest processed frame as JPEG
+//	POST   /snapshot      save the latest frame to a [snapshots] image
+//	POST   /trigger       start or extend an event clip (?event=<name>, default "api")
+//	GET    /processors    every registered processor with its parameter schema
+//	GET    /processors/{name}
+//
+// Changes go through the same validated swap as a config file reload: an
+// invalid change is rejected with 400 and the old pipeline keeps running.
+//
+// The step routes address the [pipeline] steps, or with ?camera=<name> the
+// own steps of a [[cameras]] entry; see stepsOf.
+//
+// Every route goes through guardAPI: only this machine is answered unless
+// [api] remote is set, and [api] token is checked when set.
+func (a *App) registerAPI(mux *http.ServeMux, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, a.guardAPI(h))
+	}
+
+	handle("GET "+prefix+"/config", a.apiGetConfig)
+	handle("GET "+prefix+"/steps", a.apiGetSteps)
+	handle("PATCH "+prefix+"/steps/{index}", a.apiUpdateStep)
+	handle("PUT "+prefix+"/steps/{index}", a.apiUpdateStep)
+	handle("POST "+prefix+"/pipeline", a.apiReplacePipeline)
+	handle("POST "+prefix+"/pause", a.apiPause)
+	handle("POST "+prefix+"/resume", a.apiPause)
+	handle("GET "+prefix+"/snapshot", a.apiSnapshot)
+	handle("POST "+prefix+"/snapshot", a.apiSaveSnapshot)
+	handle("POST "+prefix+"/trigger", a.apiTrigger)
+	handle("GET "+prefix+"/processors", apiListProcessors)
+	handle("GET "+prefix+"/processors/{name}", apiDescribeProcessor)
+}
+
+// guardAPI refuses requests from other machines unless [api] remote is set,
+// and requests without the bearer token when [api] token is set. The
+// settings are read per request, so a hot reload applies them.
+func (a *App) guardAPI(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.RLock()
+		remote, token := a.Config.API.Remote, a.Config.API.Token
+		a.mu.RUnlock()
+
+		if !remote && !isLoopback(r.RemoteAddr) {
+			writeError(w, http.StatusForbidden, errors.New("the control API only answers this machine; set [api] remote and token to allow others"))
+			return
+		}
+		if token != "" {
+			got := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(got, []byte("Bearer "+token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="gocvkit"`)
+				writeError(w, http.StatusUnauthorized, errors.New("missing or wrong API token"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopback reports whether addr, a request's "host:port" remote address,
+// is on this machine.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (a *App) apiGetConfig(w http.ResponseWriter, r *http.Request) {
+	a.mu.RLock()
+	cfg := a.Config.Clone()
+	a.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+func (a *App) apiGetSteps(w http.ResponseWriter, r *http.Request) {
+	a.mu.RLock()
+	cfg := a.Config.Clone()
+	a.mu.RUnlock()
+
+	steps, status, err := stepsOf(cfg, r)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, *steps)
+}
+
+// stepsOf returns the steps r addresses in cfg: the [pipeline] steps, or with
+// ?camera=<name> the steps of that [[cameras]] entry. A camera without steps
+// of its own runs the [pipeline] ones, so it is refused with 400 rather than
+// changing every such camera behind the caller's back.
+func stepsOf(cfg *config.Config, r *http.Request) (*[]config.StepConfig, int, error) {
+	name := r.URL.Query().Get("camera")
+	if name == "" {
+		return &cfg.Pipeline.Steps, 0, nil
+	}
+	for i := range cfg.Cameras {
+		e := &cfg.Cameras[i]
+		if e.Name != name {
+			continue
+		}
+		if e.Pipeline.Steps == nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("camera %q has no steps of its own and runs the [pipeline] steps; leave out ?camera", name)
+		}
+		return &e.Pipeline.Steps, 0, nil
+	}
+	return nil, http.StatusNotFound, fmt.Errorf("no camera %q", name)
+}
+
+// apiUpdateStep handles PATCH (merge keys into the step) and PUT (replace the step).
+func (a *App) apiUpdateStep(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid step index %q", r.PathValue("index")))
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	body, err := config.DecodeJSONTable(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	a.applyAPIChange(w, r, func(steps []config.StepConfig) ([]config.StepConfig, int, error) {
+		if index < 0 || index >= len(steps) {
+			return nil, http.StatusNotFound, fmt.Errorf("no pipeline step %d", index)
+		}
+
+		// PUT replaces the step; PATCH merges into its current table.
+		table := body
+		if r.Method == http.MethodPatch {
+			table = steps[index].Table()
+			for k, v := range body {
+				table[k] = v
+			}
+		}
+
+		sc, err := config.StepFromTable(table)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		steps[index] = sc
+		return steps, 0, nil
+	})
+}
+
+func (a *App) apiReplacePipeline(w http.ResponseWriter, r *http.Request) {
+	var steps []config.StepConfig
+	if err := json.NewDecoder(r.Body).Decode(&steps); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	a.applyAPIChange(w, r, func([]config.StepConfig) ([]config.StepConfig, int, error) {
+		return steps, 0, nil
+	})
+}
+
+// applyAPIChange applies change to the steps r addresses (see stepsOf) in a
+// copy of the current config and reloads it. change returns the new steps, or
+// rejects the request with an error and the status to reply with.
+// On success the new step list is written back to the client.
+func (a *App) applyAPIChange(w http.ResponseWriter, r *http.Request, change func([]config.StepConfig) ([]config.StepConfig, int, error)) {
+	a.apiMu.Lock()
+	defer a.apiMu.Unlock()
+
+	a.mu.RLock()
+	cfg := a.Config.Clone()
+	a.mu.RUnlock()
+
+	steps, status, err := stepsOf(cfg, r)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	next, status, err := change(*steps)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	*steps = next
+
+	if err := a.reload(cfg); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("pipeline rejected (old pipeline still running): %w", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, next)
+}
+
+// apiPause handles both /pause and /resume.
+func (a *App) apiPause(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/pause") {
+		a.Pause()
+	} else {
+		a.Resume()
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"paused": a.paused.Load()})
+}
+
+func (a *App) apiSnapshot(w http.ResponseWriter, r *http.Request) {
+	a.lastMu.Lock()
+	if a.last.Empty() {
+		a.lastMu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("no frame processed yet"))
+		return
+	}
+	buf, err := gocv.IMEncode(gocv.JPEGFileExt, a.last)
+	a.lastMu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(buf.GetBytes())
+}
+
+func (a *App) apiSaveSnapshot(w http.ResponseWriter, r *http.Request) {
+	files, err := a.Snapshot()
+	switch {
+	case errors.Is(err, errSnapshotsDisabled):
+		writeError(w, http.StatusConflict, err)
+	case err != nil && len(files) == 0:
+		writeError(w, http.StatusServiceUnavailable, err)
+	default:
+		if err != nil {
+			log.Printf("Snapshot error: %v", err)
+		}
+		writeJSON(w, http.StatusOK, map[string][]string{"files": files})
+	}
+}
+
+func (a *App) apiTrigger(w http.ResponseWriter, r *http.Request) {
+	event := r.URL.Query().Get("event")
+	if event == "" {
+		event = "api"
+	}
+	if !a.Trigger(event) {
+		writeError(w, http.StatusConflict, fmt.Errorf("event clips are disabled (set [clips] enabled = true)"))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"triggered": event})
+}
+
+func apiListProcessors(w http.ResponseWriter, r *http.Request) {
+	names := processor.List()
+	infos := make([]processor.Info, 0, len(names))
+	for _, name := range names {
+		info, _ := processor.Describe(name)
+		infos = append(infos, info)
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func apiDescribeProcessor(w http.ResponseWriter, r *http.Request) {
+	info, ok := processor.Describe(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown processor %q", r.PathValue("name")))
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Elliot727/gocvkit/config"
+)
+
+func TestGuardAPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote bool
+		token  string
+		addr   string // addr is the client's address
+		auth   string // auth is the Authorization header sent
+		want   int
+	}{
+		{"local", false, "", "127.0.0.1:5000", "", http.StatusOK},
+		{"local over IPv6", false, "", "[::1]:5000", "", http.StatusOK},
+		{"other machine", false, "", "192.0.2.7:5000", "", http.StatusForbidden},
+		{"remote without token header", true, "s3cret", "192.0.2.7:5000", "", http.StatusUnauthorized},
+		{"remote with wrong token", true, "s3cret", "192.0.2.7:5000", "Bearer guess", http.StatusUnauthorized},
+		{"remote with token", true, "s3cret", "192.0.2.7:5000", "Bearer s3cret", http.StatusOK},
+		{"local still needs a set token", false, "s3cret", "127.0.0.1:5000", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{Config: &config.Config{}}
+			a.Config.API.Remote = tt.remote
+			a.Config.API.Token = tt.token
+			h := a.guardAPI(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest("GET", "/api/config", nil)
+			req.RemoteAddr = tt.addr
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepsOf(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Pipeline.Steps = []config.StepConfig{{Name: "Grayscale"}}
+	cfg.Cameras = []config.CameraEntry{{Name: "left"}, {Name: "right"}}
+	cfg.Cameras[1].Pipeline.Steps = []config.StepConfig{{Name: "Canny"}}
+
+	tests := []struct {
+		query string
+		want  string // want is the first step's name, or "" for an error
+		code  int
+	}{
+		{"", "Grayscale", 0},
+		{"?camera=right", "Canny", 0},
+		{"?camera=left", "", http.StatusBadRequest}, // runs the [pipeline] steps
+		{"?camera=middle", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		steps, code, err := stepsOf(cfg, httptest.NewRequest("GET", "/api/steps"+tt.query, nil))
+		if tt.want == "" {
+			if err == nil || code != tt.code {
+				t.Errorf("%q: status %d, error %v; want %d", tt.query, code, err, tt.code)
+			}
+			continue
+		}
+		if err != nil || (*steps)[0].Name != tt.want {
+			t.Errorf("%q: got %v, %v; want the %s steps", tt.query, steps, err, tt.want)
+		}
+	}
+}
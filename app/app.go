@@ -9,25 +9,32 @@
 //
 // All concurrency, resource management, graceful shutdown (Ctrl+C, Esc/q),
 // and zero-leak pipeline swapping are handled automatically.
+//
+// On machines without a display (servers, CI) set `[app] headless = true` or
+// pass WithHeadless(); no window is created and the App stops on a signal or
+// when the input runs out.
 package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"log"
+	"maps"
 	"net/http"
-	"os"
 	"os/signal"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/Elliot727/gocvkit/builder"
 	"github.com/Elliot727/gocvkit/camera"
 	"github.com/Elliot727/gocvkit/config"
 	"github.com/Elliot727/gocvkit/display"
+	"github.com/Elliot727/gocvkit/frame"
 	"github.com/Elliot727/gocvkit/pipeline"
 	"github.com/Elliot727/gocvkit/recorder"
 	"github.com/Elliot727/gocvkit/streamer"
@@ -38,84 +45,334 @@ import (
 
 // App represents a fully configured and running computer vision application.
 type App struct {
-	mu         sync.RWMutex       // mu provides thread-safe access to mutable fields
-	Camera     *camera.Camera     // Camera handles video input from webcam or file
-	Recorder   *recorder.Recorder // Recorder manages video file output
+	mu         sync.RWMutex          // mu provides thread-safe access to mutable fields
+	Camera     camera.Source         // Camera is the frame source (webcam, file, network stream or custom)
+	Recorder   *recorder.Recorder    // Recorder writes the processed frames ([app] output, or the first [[record.outputs]] with source "output")
+	Clips      *recorder.Clipper     // Clips records event clips ([clips]; nil when disabled)
+	Snapshots  *recorder.Snapshotter // Snapshots saves frames as images ([snapshots]; nil when disabled)
 	Streamer   *streamer.MJPEGStreamer
-	Display    *display.Display   // Display shows processed frames in a window
-	Pipeline   *pipeline.Pipeline // Pipeline processes frames through configured steps
-	Config     *config.Config     // Config holds the current application configuration
-	configPath string             // configPath is the path to the config file for hot-reloading
+	Display    *display.Display     // Display shows processed frames in a window (nil when headless)
+	Pipeline   *pipeline.Pipeline   // Pipeline processes frames through configured steps
+	replicas   []*pipeline.Pipeline // replicas are extra copies of Pipeline for parallel workers
+	workers    int                  // workers is the number of goroutines running pipelines
+	Config     *config.Config       // Config holds the current application configuration
+	configPath string               // configPath is the watched config file ("" disables hot-reloading)
+	opts       []Option             // opts are the constructor options, re-applied to every reloaded config file
+
+	ignoreSignals bool          // ignoreSignals disables the built-in SIGINT/SIGTERM handler
+	server        *http.Server  // server serves the stream and metrics (nil when both are off)
+	metrics       *appMetrics   // metrics are the live health metrics served at [metrics] path
+	paused        atomic.Bool   // paused stops the reader from fetching new frames
+	steps         atomic.Int32  // steps is the number of single frames to read while paused
+	seeks         atomic.Int64  // seeks counts the seeks made; frames read before the latest are not delivered
+	shown         atomic.Int64  // shown is the source position of the frame delivered last, plus one (0 = none yet)
+	apiMu         sync.Mutex    // apiMu serialises control API changes and file reloads so none are lost
+	lastMu        sync.Mutex    // lastMu guards last
+	last          gocv.Mat      // last is a copy of the latest output frame, kept for API snapshots
+	showFPS       atomic.Bool   // showFPS draws the measured frame rate on the output ('f' key)
+	recordings    []recording   // recordings are the [record] outputs in use (none when [app] record is off)
+	taps          []string      // taps names the pipeline taps that recordings need copied out of each frame
+	done          chan struct{} // done is closed by Close to stop background goroutines
+	closeOnce     sync.Once
+
+	// Multi-camera apps ([[cameras]]) run one App per camera.
+	views   []*App             // views are the per-camera Apps, in [[cameras]] order
+	windows []*display.Display // windows show each view when not synchronised; HighGUI stays on the Run goroutine
+	view    bool               // view marks an App run by a multi-camera parent
 }
 
 // New creates and returns a new App instance from the given TOML config file.
 // Config changes are automatically detected and applied at runtime.
-func New(cfgPath string) (*App, error) {
+// Options are applied on top of the loaded configuration, and again on top
+// of every reload of the file, so settings made in code always win.
+func New(cfgPath string, opts ...Option) (*App, error) {
 	cfg, err := config.Load(cfgPath)
 	if err != nil {
 		return nil, err
 	}
 
-	cam, err := camera.NewCamera(cfg.Camera.DeviceID, cfg.Camera.File)
-	if err != nil {
-		return nil, err
-	}
+	return NewFromConfig(cfg, append([]Option{WithConfigWatch(cfgPath)}, opts...)...)
+}
 
-	output := cfg.App.Output
-	if output == "" {
-		output = "gocvkit_capture.mp4"
+// NewFromConfig creates an App from an in-memory configuration, for pipelines
+// generated in code, tests and embedded use. A nil cfg starts from defaults
+// (webcam 0, empty pipeline); options such as WithSteps fill in the rest.
+//
+// The App takes ownership of cfg. No file is watched unless WithConfigWatch
+// is passed.
+func NewFromConfig(cfg *config.Config, opts ...Option) (*App, error) {
+	if cfg == nil {
+		cfg = &config.Config{}
 	}
 
-	rec := recorder.NewRecorder(output)
+	a := &App{
+		Config: cfg,
+		done:   make(chan struct{}),
+		last:   gocv.NewMat(),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.opts = opts
 
-	win := display.New(cfg.App.WindowName)
+	// On failure, release whatever was opened so far (including a source
+	// passed with WithSource, which the App owns).
+	fail := func(err error) (*App, error) {
+		a.Close()
+		return nil, err
+	}
 
-	str := streamer.NewMJPEGStreamer()
+	cfg = a.Config
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		return fail(err)
+	}
+	if err := a.openRecorders(cfg); err != nil {
+		return fail(err)
+	}
 
-	if cfg.Stream.Quality == 0 {
-		cfg.Stream.Quality = 75
+	a.workers = max(cfg.Pipeline.Workers, 1)
+	if a.metrics == nil {
+		a.metrics = newAppMetrics(a)
 	}
 
-	steps, err := builder.BuildPipeline(cfg)
+	var p *pipeline.Pipeline
+	var replicas []*pipeline.Pipeline
+	var err error
+	if len(cfg.Cameras) > 0 {
+		p, err = a.openViews(cfg)
+	} else {
+		p, replicas, err = a.buildPipelines(cfg)
+	}
 	if err != nil {
-		cam.Close()
+		return fail(err)
+	}
 
-		win.Close()
-		return nil, err
+	// A source passed with WithSource wins over the [camera] table.
+	cam := a.Camera
+	if cam == nil && len(a.views) == 0 {
+		cam, err = camera.Open(cfg.Camera)
+		if err != nil {
+			closePipelines(p, replicas)
+			return fail(err)
+		}
+	}
+	if c, ok := cam.(*camera.Camera); ok {
+		c.OnEvent = a.metrics.cameraEvent
 	}
 
-	a := &App{
-		Camera:     cam,
-		Recorder:   rec,
-		Streamer:   str,
-		Display:    win,
-		Pipeline:   pipeline.New(steps),
-		Config:     cfg,
-		configPath: cfgPath,
+	// Views never open a window, and unsynchronised multi-camera apps have
+	// one window per view instead of their own.
+	var win *display.Display
+	if !cfg.App.Headless && !a.view && a.windows == nil {
+		win = display.New(cfg.App.WindowName)
 	}
 
-	if cfg.Stream.Enabled {
-		mux := http.NewServeMux()
-		mux.Handle(cfg.Stream.Path, str)
+	a.Camera = cam
+	a.Streamer = streamer.NewMJPEGStreamer()
+	a.Display = win
+	a.Pipeline = p
+	a.replicas = replicas
+
+	if !a.view {
+		a.startServer()
+	}
 
-		addr := fmt.Sprintf(":%d", cfg.Stream.Port)
-		go http.ListenAndServe(addr, mux)
+	if a.configPath != "" {
+		go a.watchConfig() // fire-and-forget hot reload
 	}
-	go a.watchConfig() // fire-and-forget hot reload
 	return a, nil
 }
 
-// Close releases all resources (camera, window, pipeline).
+// Close releases all resources (camera, window, pipeline) and stops the
+// stream server and config watcher. Safe to call multiple times.
 func (a *App) Close() {
-	a.Camera.Close()
-	a.Display.Close()
+	a.closeOnce.Do(a.close)
+}
+
+func (a *App) close() {
+	close(a.done)
+	if a.server != nil {
+		a.server.Close()
+	}
 
-	a.Recorder.Close()
+	if a.Camera != nil {
+		a.Camera.Close()
+	}
+	for _, v := range a.views {
+		v.Close()
+	}
+	if a.Display != nil {
+		a.Display.Close()
+	}
+	for _, w := range a.windows {
+		w.Close()
+	}
+
+	for _, rec := range a.recorders() {
+		rec.Close()
+	}
+	if a.Clips != nil {
+		a.Clips.Close()
+	}
+	if a.Snapshots != nil {
+		a.Snapshots.Close()
+	}
 	a.mu.Lock()
 	if a.Pipeline != nil {
-		a.Pipeline.Close()
+		closePipelines(a.Pipeline, a.replicas)
 	}
 	a.mu.Unlock()
+
+	a.lastMu.Lock()
+	a.last.Close()
+	a.lastMu.Unlock()
+}
+
+// Pause stops reading new frames until Resume is called. The window stays
+// responsive and outputs simply receive no new frames.
+// In a multi-camera app every camera pauses.
+func (a *App) Pause() {
+	a.paused.Store(true)
+	for _, v := range a.views {
+		v.Pause()
+	}
+}
+
+// Resume continues reading frames after Pause.
+func (a *App) Resume() {
+	a.steps.Store(0)
+	a.paused.Store(false)
+	for _, v := range a.views {
+		v.Resume()
+	}
+}
+
+// Trigger fires an event clip ([clips]) as if a frame had raised the event
+// reason; during a clip it extends the clip. In a multi-camera app every
+// camera records a clip. It reports false when clips are disabled.
+func (a *App) Trigger(reason string) bool {
+	ok := a.Clips != nil
+	if ok {
+		a.Clips.Trigger(reason)
+	}
+	for _, v := range a.views {
+		ok = v.Trigger(reason) || ok
+	}
+	return ok
+}
+
+// Snapshot saves the latest output frame to a new [snapshots] image and
+// returns its name; in a multi-camera app every camera saves one. It is safe
+// to call from any goroutine, including the frame callback, where it saves
+// the frame being delivered (as the pipeline produced it, before the
+// callback's own drawing).
+func (a *App) Snapshot() ([]string, error) {
+	if a.Snapshots == nil && len(a.views) == 0 {
+		return nil, errSnapshotsDisabled
+	}
+
+	var files []string
+	var errs []error
+	if a.Snapshots != nil {
+		name, err := a.Snapshots.Save()
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			files = append(files, name)
+		}
+	}
+	for _, v := range a.views {
+		names, err := v.Snapshot()
+		files = append(files, names...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return files, errors.Join(errs...)
+}
+
+// errSnapshotsDisabled is returned by Snapshot without [snapshots].
+var errSnapshotsDisabled = errors.New("snapshots are disabled (set [snapshots] enabled = true)")
+
+// step pauses and reads exactly one more frame (from every camera).
+func (a *App) step() {
+	a.Pause()
+	a.steps.Add(1)
+	for _, v := range a.views {
+		v.steps.Add(1)
+	}
+}
+
+// seekBy moves a seekable source (video file, image sequence) by delta
+// frames from the frame on screen, which can be well behind the reader when
+// the pipeline lags. Frames read before the seek are then skipped. It
+// reports false when the source cannot seek.
+func (a *App) seekBy(delta int) bool {
+	if len(a.views) > 0 {
+		moved := false
+		for _, v := range a.views {
+			moved = v.seekBy(delta) || moved
+		}
+		return moved
+	}
+
+	s, ok := a.Camera.(camera.Seeker)
+	if !ok {
+		return false
+	}
+	from := s.Position()
+	if n := a.shown.Load(); n > 0 {
+		from = int(n) // the frame after the one on screen
+	}
+	if err := s.Seek(from + delta); err != nil {
+		return false
+	}
+	a.seeks.Add(1)
+	return true
+}
+
+// seekSeconds moves a seekable source by secs seconds at its own frame rate.
+func (a *App) seekSeconds(secs float64) {
+	if len(a.views) > 0 {
+		for _, v := range a.views {
+			v.seekSeconds(secs)
+		}
+		return
+	}
+
+	fps := a.Camera.FPS()
+	if fps <= 0 || fps > 200 {
+		fps = 30.0
+	}
+	a.seekBy(int(secs * fps))
+}
+
+// FrameFunc is a per-frame callback that also receives the frame's metadata,
+// as filled in by the pipeline's MetaProcessable steps.
+type FrameFunc func(img *gocv.Mat, meta *frame.Meta)
+
+// result is a processed frame on its way from the pipeline to the outputs.
+type result struct {
+	img  gocv.Mat
+	meta *frame.Meta
+	cfg  *config.Config      // cfg is the configuration whose pipeline produced img (nil before processing)
+	taps map[string]gocv.Mat // taps are copies of the pipeline taps that recordings need
+	pos  int                 // pos is the frame's number in a seekable source (file, image sequence), or -1
+	seek int64               // seek is the App's seek count when the frame was read
+}
+
+// close releases the frame and its taps.
+func (r result) close() {
+	r.img.Close()
+	r.closeTaps()
+}
+
+// closeTaps releases the taps.
+func (r result) closeTaps() {
+	for _, m := range r.taps {
+		m.Close()
+	}
 }
 
 // Run starts the capture -> process -> display loop.
@@ -127,25 +384,84 @@ func (a *App) Close() {
 // Pass nil if not needed.
 //
 // Run blocks until the user presses Esc/q or sends Ctrl+C.
-// Returns any error that occurs during execution or context cancellation.
+// In headless mode it runs until Ctrl+C or the end of the input.
+// Returns any error that occurs during execution.
 func (a *App) Run(frameCallback func(*gocv.Mat)) error {
+	return a.RunContext(context.Background(), frameCallback)
+}
+
+// RunContext is like Run but also stops when ctx is cancelled, in which case
+// it returns ctx.Err() once every frame in flight has been released.
+//
+// Unless the App was created with WithoutSignalHandling, SIGINT and SIGTERM
+// still stop the loop; a signal-triggered shutdown returns nil.
+func (a *App) RunContext(ctx context.Context, frameCallback func(*gocv.Mat)) error {
+	var fn FrameFunc
+	if frameCallback != nil {
+		fn = func(img *gocv.Mat, _ *frame.Meta) { frameCallback(img) }
+	}
+	return a.RunFrames(ctx, fn)
+}
+
+// RunFrames is like RunContext, but the callback also receives the metadata
+// that the pipeline attached to each frame (detections, keypoints, tags).
+// The same metadata is written to the recorder sidecar (`[app] record_meta`)
+// and served next to the MJPEG stream at "<stream path>/meta".
+func (a *App) RunFrames(parent context.Context, frameCallback FrameFunc) error {
 	if frameCallback == nil {
-		frameCallback = func(*gocv.Mat) {}
+		frameCallback = func(*gocv.Mat, *frame.Meta) {}
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(parent)
 	defer cancel()
 
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
-	go func() { <-sig; cancel() }()
+	if !a.ignoreSignals {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+	}
 
-	frames := make(chan gocv.Mat, 10)
-	results := make(chan gocv.Mat, 10)
+	if len(a.views) > 0 {
+		return a.runViews(ctx, parent, frameCallback)
+	}
+
+	// Hot reloads replace a.Config while the App runs; read it once.
+	a.mu.RLock()
+	cfg := a.Config
+	a.mu.RUnlock()
+
+	// Queues between the reader, the pipeline and the outputs; when one is
+	// full the [pipeline] backpressure policy waits or drops the oldest frame.
+	bp := a.backpressure(cfg)
+	frames := bp.queue()
+	results := bp.queue()
+
+	// The "input" recordings are written from a queue of their own, so that
+	// encoding them never holds up the reader.
+	var inputs chan result
+	ib := a.inputQueue(bp)
+	inputsDone := make(chan struct{})
+	if a.recordsInput() {
+		inputs = ib.queue()
+		go func() {
+			defer close(inputsDone)
+			a.recordInputs(inputs)
+		}()
+	} else {
+		close(inputsDone)
+	}
+	// On exit, let the input recordings write what is queued; the reader
+	// has closed inputs by the time results are drained below.
+	defer func() {
+		cancel()
+		<-inputsDone
+	}()
 
-	delay := 1
+	// Files are paced to their frame rate times the playback speed (default
+	// 1x, with or without a window; "max" turns pacing off).
+	var interval time.Duration
 	recFPS := 30.0
-	if a.Config.Camera.File != "" {
+	if cfg.Camera.File != "" {
 		fps := a.Camera.FPS()
 
 		// Fallback for files with missing/bad metadata
@@ -153,20 +469,63 @@ func (a *App) Run(frameCallback func(*gocv.Mat)) error {
 			fps = 30.0
 		}
 		recFPS = fps
-		// 1000ms / FPS = delay in ms (e.g., 30fps -> 33ms)
-		delay = int(1000.0 / fps)
+
+		speed := cfg.Camera.Speed
+		if speed == 0 {
+			speed = 1
+		}
+		// 1s / (FPS × speed) between frames (e.g., 30fps at 2x -> 16ms); 0 at "max"
+		interval = time.Duration(float64(time.Second) / (fps * float64(speed)))
 	}
 
-	if a.Config.App.Record {
+	if len(a.recordings) > 0 || a.Clips != nil {
 		if fps := a.Camera.FPS(); fps > 0 {
 			recFPS = fps
 		}
-		a.Recorder.SetFPS(recFPS)
+		a.configureRecorder(cfg, recFPS)
 	}
 
 	go func() {
 		defer close(frames)
+		if inputs != nil {
+			defer close(inputs)
+		}
+
+		// Measure the camera read rate once per second for the metrics.
+		readCount := 0
+		readTicker := time.Now()
+		var index int64
+
+		var due time.Time
 		for ctx.Err() == nil {
+			// While paused, leave the source alone (a file keeps its position),
+			// apart from single steps requested with the '.' key.
+			if a.paused.Load() {
+				if a.steps.Load() == 0 {
+					select {
+					case <-time.After(20 * time.Millisecond):
+					case <-ctx.Done():
+					}
+					continue
+				}
+				a.steps.Add(-1)
+			} else if interval > 0 {
+				if wait := time.Until(due); wait > 0 {
+					select {
+					case <-time.After(wait):
+					case <-ctx.Done():
+						return
+					}
+				}
+				due = time.Now().Add(interval)
+			}
+
+			// Note where the frame comes from, for seeks relative to it.
+			seek, pos := a.seeks.Load(), -1
+			if s, ok := a.Camera.(camera.Seeker); ok {
+				pos = s.Position()
+			}
+
 			img := gocv.NewMat()
 
 			// Read frame
@@ -175,44 +534,60 @@ func (a *App) Run(frameCallback func(*gocv.Mat)) error {
 				return // End of file or error
 			}
 
-			select {
-			case frames <- img:
-				// Frame sent successfully
-			case <-ctx.Done():
-				img.Close()
-				return
+			// Stamp the frame as soon as it arrives: its sequence number,
+			// capture time and position in the source travel with it.
+			meta := frame.New(index)
+			index++
+			if t, ok := a.Camera.(camera.Timestamper); ok {
+				meta.Position = t.Timestamp()
+			}
+			if n, ok := a.Camera.(camera.Namer); ok {
+				if name := n.Filename(); name != "" {
+					meta.SetTag("file", name)
+				}
 			}
-		}
-	}()
-
-	go func() {
-		defer close(results)
-		for img := range frames {
-			out := gocv.NewMat()
-
-			a.mu.RLock()
-			err := a.Pipeline.Run(img, &out)
-			a.mu.RUnlock()
 
-			img.Close() // We are done with the input frame
+			if inputs != nil {
+				// The pipeline adds to meta meanwhile; record it as read.
+				in := *meta
+				in.Tags = maps.Clone(meta.Tags)
+				if !ib.send(ctx, inputs, result{img: img.Clone(), meta: &in}) {
+					img.Close()
+					return
+				}
+			}
 
-			if err != nil {
-				out.Close()
-				log.Printf("Pipeline error: %v", err)
-				continue
+			a.metrics.framesRead.Inc()
+			readCount++
+			if elapsed := time.Since(readTicker); elapsed >= time.Second {
+				a.metrics.cameraFPS.Set(float64(readCount) / elapsed.Seconds())
+				readCount = 0
+				readTicker = time.Now()
 			}
 
-			select {
-			case results <- out:
-			case <-ctx.Done():
-				out.Close()
+			if !bp.send(ctx, frames, result{img: img, meta: meta, pos: pos, seek: seek}) {
 				return
 			}
 		}
 	}()
 
-	showFPS := false
+	// On exit, stop the workers and release any frames still queued.
+	defer func() {
+		cancel()
+		for r := range results {
+			r.close()
+		}
+	}()
+
+	go a.process(ctx, frames, results, bp)
+
+	return a.deliver(ctx, parent, results, frameCallback)
+}
 
+// deliver runs the callback on every result and sends it to the outputs
+// (FPS overlay, recorder, stream, window), handling the keyboard, until the
+// results run out, the user quits or ctx is cancelled.
+func (a *App) deliver(ctx, parent context.Context, results <-chan result, frameCallback FrameFunc) error {
 	// Setup "Bucket" variables for stable FPS calculation
 	fpsTicker := time.Now() // The starting gun
 	fpsCounter := 0         // The bucket of frames
@@ -222,17 +597,64 @@ func (a *App) Run(frameCallback func(*gocv.Mat)) error {
 	green := color.RGBA{0, 255, 0, 0}
 	blackShadow := color.RGBA{0, 0, 0, 0}
 
+	// While paused no frames arrive, so poll the window on a timer to keep it
+	// responsive (and still honour the keys).
+	idle := time.NewTicker(100 * time.Millisecond)
+	defer idle.Stop()
+
+	// applied is the configuration behind the last frame; a frame made by
+	// another one is where a hot reload took effect.
+	a.mu.RLock()
+	applied := a.Config
+	a.mu.RUnlock()
+
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case m, ok := <-results:
+			return parent.Err()
+		case <-idle.C:
+			if a.Display == nil || !a.paused.Load() {
+				continue
+			}
+			if a.handleKey(a.Display.Key(1)) {
+				return nil
+			}
+		case r, ok := <-results:
 			if !ok {
 				return nil
 			}
+			// Frames read before a seek were queued behind the frame on
+			// screen; showing them would undo the seek.
+			if r.seek != a.seeks.Load() {
+				r.close()
+				continue
+			}
+			if r.pos >= 0 {
+				a.shown.Store(int64(r.pos) + 1)
+			}
+			m := r.img
+
+			if r.cfg != nil && r.cfg != applied {
+				applied = r.cfg
+				for _, rc := range a.recordings {
+					if rc.source != config.SourceInput {
+						rc.rec.NoteReload(r.meta.Index, r.cfg)
+					}
+				}
+				if a.Clips != nil {
+					a.Clips.SetConfig(r.cfg)
+				}
+			}
+
+			// Snapshots keep the frame as the pipeline made it.
+			if a.Snapshots != nil {
+				if err := a.Snapshots.WriteMeta(m, r.meta); err != nil {
+					log.Printf("Snapshot error: %v", err)
+				}
+			}
 
 			// 1. Run User Callback
-			frameCallback(&m)
+			frameCallback(&m, r.meta)
 
 			// 2. Update FPS Logic
 			fpsCounter++
@@ -249,7 +671,7 @@ func (a *App) Run(frameCallback func(*gocv.Mat)) error {
 			}
 
 			// 3. Draw the Overlay (if enabled)
-			if showFPS {
+			if a.showFPS.Load() {
 				// FIX: If image is Grayscale (1-channel), convert to BGR (3-channel).
 				// Otherwise, Green text (0, 255, 0) is drawn as Black (0) on a Black background.
 				if m.Channels() == 1 {
@@ -262,36 +684,102 @@ func (a *App) Run(frameCallback func(*gocv.Mat)) error {
 			}
 
 			// 4. Record (Smart Recorder handles format changes)
-			if a.Config.App.Record {
-				a.Recorder.Write(m)
+			a.recordOutput(m, r)
+			r.closeTaps()
+
+			if a.Clips != nil {
+				a.Clips.WriteMeta(m, r.meta)
+			}
+
+			if applied.Stream.Enabled {
+				a.Streamer.Broadcast(m, applied.Stream.Quality)
+				a.Streamer.PublishMeta(r.meta)
+			}
+
+			if applied.API.Enabled {
+				a.lastMu.Lock()
+				m.CopyTo(&a.last)
+				a.lastMu.Unlock()
 			}
 
-			if a.Config.Stream.Enabled {
-				a.Streamer.Broadcast(m, a.Config.Stream.Quality)
+			a.metrics.processed.Inc()
+			a.metrics.frameLatency.Observe(time.Since(r.meta.Timestamp).Seconds())
+
+			// Headless: nothing to show and no keyboard to poll.
+			if a.Display == nil {
+				m.Close()
+				continue
 			}
 
 			// 5. Display
 			a.Display.Show(m)
 
-			// 6. Handle Input
-			// Use the calculated 'delay' from earlier in the function
-			key := a.Display.Key(delay)
-
-			// Quit on 'q' or Esc (27)
-			if key == 27 || key == 'q' || key == 'Q' {
+			// 6. Handle Input (the reader paces files, so just poll)
+			quit := a.handleKey(a.Display.Key(1))
+			m.Close()
+			if quit {
 				return nil
 			}
+		}
+	}
+}
 
-			// Toggle FPS on 'f'
-			if key == 'f' || key == 'F' {
-				showFPS = !showFPS
-			}
+// handleKey applies a key press and reports whether to quit.
+func (a *App) handleKey(key int) bool {
+	switch key {
+	case 27, 'q', 'Q': // Quit on 'q' or Esc (27)
+		return true
+	case 'f', 'F': // Toggle FPS on 'f'
+		a.toggleFPS()
+	case 's', 'S': // Save a snapshot
+		if _, err := a.Snapshot(); err != nil {
+			log.Printf("Snapshot error: %v", err)
+		}
+	case ' ': // Pause / resume
+		if a.paused.Load() {
+			a.Resume()
+		} else {
+			a.Pause()
+		}
+	case '.': // Single step forward (pauses first)
+		a.step()
+	case ',': // Single step back
+		a.Pause()
+		if a.seekBy(-2) {
+			a.step()
+		}
+	case '[': // Seek back 5 seconds
+		a.seekSeconds(-5)
+	case ']': // Seek forward 5 seconds
+		a.seekSeconds(5)
+	}
+	return false
+}
 
-			m.Close()
+// toggleFPS switches the FPS overlay on or off. In a multi-camera app with a
+// window per camera, it switches every camera's overlay too.
+func (a *App) toggleFPS() {
+	on := !a.showFPS.Load()
+	a.showFPS.Store(on)
+	if len(a.windows) > 0 {
+		for _, v := range a.views {
+			v.showFPS.Store(on)
 		}
 	}
 }
 
+// withOptions applies the constructor options to cfg, a freshly loaded
+// config file, so that what they set (WithSteps, WithHeadless, WithStream,
+// ...) survives hot reloads. The options run against a scratch App: only
+// their effect on the config is kept.
+func (a *App) withOptions(cfg *config.Config) *config.Config {
+	scratch := &App{Config: cfg}
+	for _, opt := range a.opts {
+		opt(scratch)
+	}
+	return scratch.Config
+}
+
 // watchConfig monitors the config file and safely replaces the pipeline on change.
 func (a *App) watchConfig() {
 	watcher, err := fsnotify.NewWatcher()
@@ -309,6 +797,8 @@ func (a *App) watchConfig() {
 	var last time.Time
 	for {
 		select {
+		case <-a.done:
+			return
 		case ev, ok := <-watcher.Events:
 			if !ok || ev.Op&fsnotify.Write == 0 {
 				continue
@@ -323,34 +813,22 @@ func (a *App) watchConfig() {
 			cfg, err := config.Load(a.configPath)
 			if err != nil {
 				log.Printf("❌ Config reload failed: %v", err)
+				a.metrics.reloads.With("failure").Inc()
 				continue // Skip to next event
 			}
+			cfg = a.withOptions(cfg)
 
-			// 2. Build Pipeline
-			steps, err := builder.BuildPipeline(cfg)
+			// 2. Build and swap the pipeline, one change at a time with the
+			// control API
+			a.apiMu.Lock()
+			err = a.reload(cfg)
+			a.apiMu.Unlock()
 			if err != nil {
 				// CRITICAL: Log the error here so the user sees it!
 				log.Printf("Pipeline build failed (config ignored): %v", err)
 				continue // Keep running with the OLD pipeline
 			}
 
-			// 3. Swap Pipeline
-			newP := pipeline.New(steps)
-
-			a.mu.Lock()
-			old := a.Pipeline
-			a.Pipeline = newP
-			a.Config = cfg
-			a.mu.Unlock()
-
-			// 4. Cleanup Old Pipeline safely
-			if old != nil {
-				// Give the running loop a moment to finish using the old pipeline
-				time.AfterFunc(150*time.Millisecond, func() {
-					old.Close()
-				})
-			}
-
 			log.Println("Pipeline hot-reloaded successfully!")
 
 		case err, ok := <-watcher.Errors:
@@ -361,3 +839,110 @@ func (a *App) watchConfig() {
 		}
 	}
 }
+
+// reload validates cfg by building its pipeline and, only if that succeeds,
+// swaps the new pipeline and config in. On failure the running pipeline is
+// left untouched. It is shared by the file watcher and the control API.
+//
+// A multi-camera app rebuilds every camera's pipeline and the [sync]
+// pipeline, and swaps them only once all of them have been built.
+func (a *App) reload(cfg *config.Config) error {
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		a.metrics.reloads.With("failure").Inc()
+		return err
+	}
+
+	if max(cfg.Pipeline.Workers, 1) != a.workers && len(a.views) == 0 {
+		log.Printf("pipeline.workers changed to %d; takes effect on restart", cfg.Pipeline.Workers)
+	}
+	// The recorders are opened with the App, so what is recorded is fixed.
+	a.mu.RLock()
+	old := a.Config
+	a.mu.RUnlock()
+	if cfg.App.Record != old.App.Record {
+		log.Printf("app.record changed to %t; takes effect on restart", cfg.App.Record)
+	} else if cfg.App.Record && !reflect.DeepEqual(cfg.Recordings(), old.Recordings()) {
+		log.Printf("recordings changed; take effect on restart")
+	}
+	// The queues and the sources are set up when Run starts.
+	if cfg.Pipeline.Backpressure != old.Pipeline.Backpressure || cfg.Pipeline.QueueDepth != old.Pipeline.QueueDepth {
+		log.Printf("pipeline.backpressure and queue_depth changed to %s, %d; take effect on restart", cfg.Pipeline.Backpressure, cfg.Pipeline.QueueDepth)
+	}
+	if !reflect.DeepEqual(cfg.Camera, old.Camera) {
+		log.Printf("[camera] changed; takes effect on restart")
+	}
+	for i := range min(len(cfg.Cameras), len(old.Cameras)) {
+		if !reflect.DeepEqual(cfg.Cameras[i].CameraConfig, old.Cameras[i].CameraConfig) {
+			log.Printf("camera %q source settings changed; take effect on restart", cfg.Cameras[i].Name)
+		}
+	}
+	if len(cfg.Cameras) != len(a.views) {
+		a.metrics.reloads.With("failure").Inc()
+		return fmt.Errorf("the number of [[cameras]] changed from %d to %d; restart to apply", len(a.views), len(cfg.Cameras))
+	}
+
+	type built struct {
+		cfg      *config.Config
+		p        *pipeline.Pipeline
+		replicas []*pipeline.Pipeline
+	}
+	var views []built
+	fail := func(err error) error {
+		for _, b := range views {
+			closePipelines(b.p, b.replicas)
+		}
+		a.metrics.reloads.With("failure").Inc()
+		return err
+	}
+
+	for i, v := range a.views {
+		vc := cfg.ForCamera(i)
+		vc.SetDefaults()
+		p, replicas, err := v.buildPipelines(vc)
+		if err != nil {
+			return fail(fmt.Errorf("camera %q: %w", cfg.Cameras[i].Name, err))
+		}
+		views = append(views, built{vc, p, replicas})
+	}
+
+	pcfg := cfg
+	if len(a.views) > 0 {
+		pcfg = syncConfig(cfg)
+	}
+	newP, newReplicas, err := a.buildPipelines(pcfg)
+	if err != nil {
+		return fail(err)
+	}
+	if len(a.views) > 0 {
+		if err := checkSync(newP); err != nil {
+			closePipelines(newP, newReplicas)
+			return fail(err)
+		}
+	}
+
+	for i, b := range views {
+		a.views[i].swap(b.cfg, b.p, b.replicas)
+	}
+	a.swap(cfg, newP, newReplicas)
+
+	a.metrics.reloads.With("success").Inc()
+	return nil
+}
+
+// swap replaces the running pipeline and config.
+func (a *App) swap(cfg *config.Config, p *pipeline.Pipeline, replicas []*pipeline.Pipeline) {
+	a.mu.Lock()
+	old, oldReplicas := a.Pipeline, a.replicas
+	a.Pipeline, a.replicas = p, replicas
+	a.Config = cfg
+	a.mu.Unlock()
+
+	// Cleanup Old Pipeline safely
+	if old != nil {
+		// Give the running loop a moment to finish using the old pipeline
+		time.AfterFunc(150*time.Millisecond, func() {
+			closePipelines(old, oldReplicas)
+		})
+	}
+}
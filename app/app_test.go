@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Elliot727/gocvkit/camera"
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/frame"
+
+	"gocv.io/x/gocv"
+)
+
+func TestRunContextCancel(t *testing.T) {
+	gen, err := camera.NewGenerator(camera.GeneratorOptions{Width: 64, Height: 48})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewFromConfig(&config.Config{}, WithSource(gen), WithHeadless(), WithoutSignalHandling())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var n int
+	done := make(chan error)
+	go func() {
+		done <- a.RunContext(ctx, func(*gocv.Mat) {
+			if n++; n == 3 {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("RunContext() = %v, want context.Canceled", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("RunContext did not return after cancel")
+	}
+}
+
+func TestRunContextEndOfInput(t *testing.T) {
+	gen, err := camera.NewGenerator(camera.GeneratorOptions{Width: 64, Height: 48, Frames: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewFromConfig(&config.Config{}, WithSource(gen), WithHeadless(), WithoutSignalHandling())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	n := 0
+	if err := a.RunContext(context.Background(), func(*gocv.Mat) { n++ }); err != nil {
+		t.Errorf("RunContext() = %v at the end of the input, want nil", err)
+	}
+	if n != 5 {
+		t.Errorf("callback ran %d times, want 5", n)
+	}
+}
+
+// Frames of an image sequence carry the name of their file.
+func TestImagesFileTag(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"frame_1.png", "frame_2.png", "frame_3.png"}
+	for _, name := range names {
+		img := gocv.NewMatWithSize(48, 64, gocv.MatTypeCV8UC3)
+		ok := gocv.IMWrite(filepath.Join(dir, name), img)
+		img.Close()
+		if !ok {
+			t.Fatalf("could not write %s", name)
+		}
+	}
+
+	cfg := &config.Config{}
+	cfg.Camera.Images = filepath.Join(dir, "*.png")
+	a, err := NewFromConfig(cfg, WithHeadless(), WithoutSignalHandling())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	var got []string
+	err = a.RunFrames(context.Background(), func(_ *gocv.Mat, meta *frame.Meta) {
+		v, _ := meta.Tag("file")
+		name, _ := v.(string)
+		got = append(got, filepath.Base(name))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(names) {
+		t.Fatalf("file tags %v, want %v", got, names)
+	}
+	for i := range names {
+		if got[i] != names[i] {
+			t.Errorf("frame %d tagged %q, want %q", i, got[i], names[i])
+		}
+	}
+}
+
+// Every frame is stamped at capture with its sequence number, capture time
+// and position in the source.
+func TestFrameStamps(t *testing.T) {
+	gen, err := camera.NewGenerator(camera.GeneratorOptions{Width: 64, Height: 48, Frames: 10, FPS: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewFromConfig(&config.Config{}, WithSource(gen), WithHeadless(), WithoutSignalHandling())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	start := time.Now()
+	var prev *frame.Meta
+	n := 0
+	err = a.RunFrames(context.Background(), func(_ *gocv.Mat, meta *frame.Meta) {
+		if meta.Index != int64(n) {
+			t.Errorf("frame %d has index %d", n, meta.Index)
+		}
+		if meta.Timestamp.Before(start) || meta.Timestamp.After(time.Now()) {
+			t.Errorf("frame %d captured at %v, outside the run", n, meta.Timestamp)
+		}
+		if prev != nil && meta.Timestamp.Before(prev.Timestamp) {
+			t.Errorf("frame %d captured before frame %d", n, n-1)
+		}
+		want := time.Duration(n) * 20 * time.Millisecond
+		if d := meta.Position - want; d < -time.Microsecond || d > time.Microsecond {
+			t.Errorf("frame %d at position %v, want %v", n, meta.Position, want)
+		}
+		prev = meta
+		n++
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 10 {
+		t.Errorf("callback ran %d times, want 10", n)
+	}
+}
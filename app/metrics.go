@@ -0,0 +1,77 @@
+package app
+
+import (
+	"time"
+
+	"github.com/Elliot727/gocvkit/camera"
+	"github.com/Elliot727/gocvkit/metrics"
+)
+
+// appMetrics holds the live health metrics of an App.
+// They are always collected and served at `[metrics] path` when enabled.
+type appMetrics struct {
+	reg          *metrics.Registry
+	stepCalls    *metrics.CounterVec   // stepCalls counts executions per pipeline step
+	stepTime     *metrics.HistogramVec // stepTime is the latency per pipeline step
+	frameLatency *metrics.Histogram    // frameLatency is the time from capture to leaving the outputs
+	framesRead   *metrics.Counter      // framesRead counts frames read from the camera
+	cameraFPS    *metrics.Gauge        // cameraFPS is the measured camera read rate
+	processed    *metrics.Counter      // processed counts frames delivered to the outputs
+	dropped      *metrics.CounterVec   // dropped counts frames that never reached the outputs, by reason
+	reloads      *metrics.CounterVec   // reloads counts hot reloads by result
+	camEvents    *metrics.CounterVec   // camEvents counts network stream disconnects and reconnects
+}
+
+func newAppMetrics(a *App) *appMetrics {
+	reg := metrics.NewRegistry()
+	m := &appMetrics{
+		reg:          reg,
+		stepCalls:    reg.CounterVec("gocvkit_step_calls_total", "Number of times each pipeline step ran.", "step"),
+		stepTime:     reg.HistogramVec("gocvkit_step_duration_seconds", "Latency of each pipeline step.", metrics.DefBuckets, "step"),
+		frameLatency: reg.Histogram("gocvkit_frame_latency_seconds", "End-to-end latency from capture to output.", metrics.DefBuckets),
+		framesRead:   reg.Counter("gocvkit_camera_frames_total", "Frames read from the camera."),
+		cameraFPS:    reg.Gauge("gocvkit_camera_fps", "Measured camera read rate in frames per second."),
+		processed:    reg.Counter("gocvkit_frames_processed_total", "Frames delivered to the outputs."),
+		dropped:      reg.CounterVec("gocvkit_frames_dropped_total", "Frames dropped before reaching the outputs, by reason (error, queue, sync, record).", "reason"),
+		reloads:      reg.CounterVec("gocvkit_config_reloads_total", "Hot reloads by result.", "result"),
+		camEvents:    reg.CounterVec("gocvkit_camera_stream_events_total", "Network stream disconnects and reconnects.", "event"),
+	}
+
+	// A multi-camera App adds up its own outputs and those of every camera.
+	reg.GaugeFunc("gocvkit_stream_clients", "Connected MJPEG stream clients.", func() float64 {
+		n := 0
+		for _, a := range append([]*App{a}, a.views...) {
+			if a.Streamer != nil {
+				n += a.Streamer.Clients()
+			}
+		}
+		return float64(n)
+	})
+	reg.CounterFunc("gocvkit_recorder_bytes_written_total", "Video bytes written by the recorders and event clips.", func() float64 {
+		var n int64
+		for _, a := range append([]*App{a}, a.views...) {
+			if a.Recorder != nil {
+				for _, rec := range a.recorders() {
+					n += rec.BytesWritten()
+				}
+			}
+			if a.Clips != nil {
+				n += a.Clips.BytesWritten()
+			}
+		}
+		return float64(n)
+	})
+
+	return m
+}
+
+// cameraEvent counts network stream events from the camera.
+func (m *appMetrics) cameraEvent(e camera.Event) {
+	m.camEvents.With(e.Type.String()).Inc()
+}
+
+// observeStep is the pipeline observer feeding the per-step metrics.
+func (m *appMetrics) observeStep(step string, elapsed time.Duration) {
+	m.stepCalls.With(step).Inc()
+	m.stepTime.With(step).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,421 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/display"
+	"github.com/Elliot727/gocvkit/frame"
+	"github.com/Elliot727/gocvkit/pipeline"
+	"github.com/Elliot727/gocvkit/processor"
+
+	// Tile is the default [sync] step.
+	_ "github.com/Elliot727/gocvkit/processor/multi"
+
+	"gocv.io/x/gocv"
+)
+
+// withView makes the App one camera of a multi-camera App, which owns the
+// windows, the HTTP server and signal handling, and shares its metrics.
+func withView(m *appMetrics) Option {
+	return func(a *App) {
+		a.view = true
+		a.metrics = m
+		a.ignoreSignals = true
+	}
+}
+
+// openViews opens one App per [[cameras]] entry and builds the [sync]
+// pipeline that combines their frames (empty when sync is off).
+func (a *App) openViews(cfg *config.Config) (*pipeline.Pipeline, error) {
+	a.workers = 1 // groups are combined in order, on one pipeline
+
+	p, _, err := a.buildPipelines(syncConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSync(p); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	for i := range cfg.Cameras {
+		v, err := NewFromConfig(cfg.ForCamera(i), withView(a.metrics))
+		if err != nil {
+			for _, v := range a.views {
+				v.Close()
+			}
+			a.views = nil
+			p.Close()
+			return nil, fmt.Errorf("camera %q: %w", cfg.Cameras[i].Name, err)
+		}
+		a.views = append(a.views, v)
+	}
+
+	if !cfg.App.Headless && !cfg.Sync.Enabled {
+		for _, v := range a.views {
+			a.windows = append(a.windows, display.New(v.Config.App.WindowName))
+		}
+	}
+	return p, nil
+}
+
+// syncConfig returns cfg with the [sync] steps as its pipeline: Tile when
+// none are given, and no steps at all when sync is off.
+func syncConfig(cfg *config.Config) *config.Config {
+	sc := cfg.Clone()
+	sc.Cameras = nil
+	sc.Pipeline.Steps = nil
+	if cfg.Sync.Enabled {
+		sc.Pipeline.Steps = sc.Sync.Steps
+		if len(sc.Pipeline.Steps) == 0 {
+			sc.Pipeline.Steps = []config.StepConfig{{Name: "Tile"}}
+		}
+	}
+	return sc
+}
+
+// checkSync checks that a [sync] pipeline starts by combining the views.
+func checkSync(p *pipeline.Pipeline) error {
+	if len(p.Steps) == 0 {
+		return nil
+	}
+	if _, ok := p.Steps[0].(processor.MultiView); !ok {
+		return fmt.Errorf("sync step 0 (%s) cannot combine camera views; start [[sync.steps]] with a multi-view step such as Tile", p.Steps[0].Name())
+	}
+	return nil
+}
+
+// viewFrame is a processed frame from one camera of a multi-camera App.
+type viewFrame struct {
+	view int // view is the camera's index in [[cameras]]
+	img  gocv.Mat
+	meta *frame.Meta
+}
+
+// runViews runs every camera's App until the user quits or ctx is cancelled.
+//
+// Without [sync] each camera records and streams its own frames, the
+// callback is called for every camera's frames (one at a time, tagged
+// "camera") and each camera has its own window; the App ends once every
+// camera has run out of frames.
+//
+// With [sync] the cameras still record and stream their own frames, while
+// groups of frames with the nearest timestamps go through the sync pipeline
+// and then to the callback and the App's own outputs. The App ends as soon as
+// any camera runs out, since no further group can be completed.
+func (a *App) runViews(ctx, parent context.Context, frameCallback FrameFunc) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Hot reloads replace a.Config while the cameras run; read it once.
+	a.mu.RLock()
+	cfg := a.Config
+	a.mu.RUnlock()
+
+	synced := cfg.Sync.Enabled
+	forward := synced || len(a.windows) > 0 || cfg.API.Enabled
+
+	frames := make(chan viewFrame, 10*len(a.views))
+	errs := make([]error, len(a.views)) // errs holds why each camera stopped
+	var callbackMu sync.Mutex
+	var wg sync.WaitGroup
+	for i, v := range a.views {
+		name := cfg.Cameras[i].Name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if synced {
+				defer cancel()
+			}
+			viewErr := v.RunFrames(ctx, func(img *gocv.Mat, meta *frame.Meta) {
+				meta.SetTag("camera", name)
+				if !synced {
+					callbackMu.Lock()
+					frameCallback(img, meta)
+					callbackMu.Unlock()
+				}
+				if !forward {
+					return
+				}
+
+				f := viewFrame{view: i, img: img.Clone(), meta: meta}
+				select {
+				case frames <- f:
+				case <-ctx.Done():
+					f.img.Close()
+				}
+			})
+			// Being stopped by runViews is not an error of the camera.
+			if viewErr != nil && !errors.Is(viewErr, ctx.Err()) {
+				errs[i] = fmt.Errorf("camera %s: %w", name, viewErr)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(frames)
+	}()
+
+	// On exit, stop the cameras and release any frames still queued; frames
+	// is closed once every camera has stopped, so errs is complete.
+	defer func() {
+		cancel()
+		for f := range frames {
+			f.img.Close()
+		}
+		err = errors.Join(err, errors.Join(errs...))
+	}()
+
+	if !synced {
+		return a.showViews(ctx, parent, frames)
+	}
+
+	if len(a.recordings) > 0 || a.Clips != nil {
+		fps := 30.0
+		if f := a.views[0].Camera.FPS(); f > 0 {
+			fps = f
+		}
+		a.configureRecorder(cfg, fps)
+	}
+
+	bp := a.backpressure(cfg)
+	results := bp.queue()
+	defer func() {
+		cancel()
+		for r := range results {
+			r.close()
+		}
+	}()
+	go a.syncViews(ctx, frames, results, bp)
+
+	return a.deliver(ctx, parent, results, frameCallback)
+}
+
+// showViews shows every camera's frames in its own window until the cameras
+// stop, the user quits or ctx is cancelled.
+//
+// HighGUI reports a key press without the window it was made in, so keys
+// act on the whole App (every camera) whichever window has focus.
+func (a *App) showViews(ctx, parent context.Context, frames <-chan viewFrame) error {
+	idle := time.NewTicker(100 * time.Millisecond)
+	defer idle.Stop()
+
+	a.mu.RLock()
+	cfg := a.Config
+	a.mu.RUnlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return parent.Err()
+		case <-idle.C:
+			if len(a.windows) == 0 || !a.paused.Load() {
+				continue
+			}
+			// No frames arrive while paused; any window reads the keys of all.
+			if a.handleKey(a.windows[0].Key(1)) {
+				return nil
+			}
+		case f, ok := <-frames:
+			if !ok {
+				return nil
+			}
+
+			if cfg.API.Enabled {
+				a.lastMu.Lock()
+				f.img.CopyTo(&a.last)
+				a.lastMu.Unlock()
+			}
+
+			if len(a.windows) == 0 {
+				f.img.Close()
+				continue
+			}
+			w := a.windows[f.view]
+			w.Show(f.img)
+			f.img.Close()
+			if a.handleKey(w.Key(1)) {
+				return nil
+			}
+		}
+	}
+}
+
+// syncViews groups the cameras' frames by capture time and runs each group
+// through the sync pipeline. It closes results when frames is exhausted.
+func (a *App) syncViews(ctx context.Context, frames <-chan viewFrame, results chan result, bp backpressure) {
+	defer close(results)
+
+	a.mu.RLock()
+	tolerance := a.Config.Sync.Tolerance.D()
+	a.mu.RUnlock()
+	s := newSyncer(len(a.views), tolerance)
+	defer s.close()
+
+	var index int64
+	for f := range frames {
+		groups, dropped := s.add(f)
+		for _, d := range dropped {
+			d.img.Close()
+			a.dropped(d.meta, "sync")
+		}
+
+		for _, g := range groups {
+			r, ok := a.combine(index, g)
+			index++
+			if !ok {
+				continue
+			}
+
+			bp.send(ctx, results, r)
+		}
+	}
+}
+
+// combine runs one group of frames through the sync pipeline and releases
+// them. The result's meta has the group's index, the capture time of its
+// oldest frame and, under the "views" tag, the meta of every frame.
+func (a *App) combine(index int64, group []viewFrame) (result, bool) {
+	views := make([]gocv.Mat, len(group))
+	metas := make([]*frame.Meta, len(group))
+	meta := frame.New(index)
+	for i, f := range group {
+		views[i] = f.img
+		metas[i] = f.meta
+		if f.meta.Timestamp.Before(meta.Timestamp) {
+			meta.Timestamp = f.meta.Timestamp
+		}
+	}
+	meta.SetTag("views", metas)
+
+	out := gocv.NewMat()
+	a.mu.RLock()
+	cfg := a.Config
+	err := a.Pipeline.RunViews(meta, views, &out)
+	var taps map[string]gocv.Mat
+	if err == nil {
+		taps = a.copyTaps(a.Pipeline)
+	}
+	a.mu.RUnlock()
+
+	for _, f := range group {
+		f.img.Close()
+	}
+
+	if err != nil {
+		out.Close()
+		log.Printf("Sync pipeline error: %v", err)
+		a.dropped(meta, "error")
+		return result{}, false
+	}
+	return result{img: out, meta: meta, cfg: cfg, taps: taps, pos: -1}, true
+}
+
+// syncer matches frames from several cameras by capture timestamp.
+type syncer struct {
+	queues    [][]viewFrame // queues holds each camera's unmatched frames, oldest first
+	tolerance time.Duration // tolerance is the largest distance from a group's reference time
+	limit     int           // limit is the most frames kept per camera while waiting for the others
+}
+
+func newSyncer(views int, tolerance time.Duration) *syncer {
+	return &syncer{
+		queues:    make([][]viewFrame, views),
+		tolerance: tolerance,
+		limit:     8,
+	}
+}
+
+// add queues f and returns every group that can now be completed, one frame
+// per camera in camera order, together with the frames that can no longer
+// be part of any group. The caller owns both.
+//
+// A group is formed around the newest of the oldest queued frames: every
+// camera contributes its frame nearest to that time, and the frames older
+// than it are dropped. When some camera has nothing within the tolerance,
+// the oldest queued frame overall is dropped and matching starts again.
+func (s *syncer) add(f viewFrame) (groups [][]viewFrame, dropped []viewFrame) {
+	q := append(s.queues[f.view], f)
+	if len(q) > s.limit {
+		dropped = append(dropped, q[0])
+		q = q[1:]
+	}
+	s.queues[f.view] = q
+
+	for s.ready() {
+		var ref time.Time
+		oldest := 0
+		for i, q := range s.queues {
+			t := q[0].meta.Timestamp
+			if t.After(ref) {
+				ref = t
+			}
+			if t.Before(s.queues[oldest][0].meta.Timestamp) {
+				oldest = i
+			}
+		}
+
+		picks := make([]int, len(s.queues))
+		matched := true
+		for i, q := range s.queues {
+			for j := range q {
+				if distance(q[j], ref) < distance(q[picks[i]], ref) {
+					picks[i] = j
+				}
+			}
+			if distance(q[picks[i]], ref) > s.tolerance {
+				matched = false
+			}
+		}
+
+		if !matched {
+			dropped = append(dropped, s.queues[oldest][0])
+			s.queues[oldest] = s.queues[oldest][1:]
+			continue
+		}
+
+		group := make([]viewFrame, len(s.queues))
+		for i, q := range s.queues {
+			dropped = append(dropped, q[:picks[i]]...)
+			group[i] = q[picks[i]]
+			s.queues[i] = q[picks[i]+1:]
+		}
+		groups = append(groups, group)
+	}
+	return groups, dropped
+}
+
+// ready reports whether every camera has a frame queued.
+func (s *syncer) ready() bool {
+	for _, q := range s.queues {
+		if len(q) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// close releases the frames still waiting for a group.
+func (s *syncer) close() {
+	for i, q := range s.queues {
+		for _, f := range q {
+			f.img.Close()
+		}
+		s.queues[i] = nil
+	}
+}
+
+// distance is how far f was captured from t.
+func distance(f viewFrame, t time.Time) time.Duration {
+	d := f.meta.Timestamp.Sub(t)
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Elliot727/gocvkit/frame"
+
+	"gocv.io/x/gocv"
+)
+
+// syncLog feeds frames to a syncer and writes down what comes out, as
+// "group 3+4" and "drop 1" lines.
+type syncLog struct {
+	s    *syncer
+	base time.Time
+	out  []string
+}
+
+// add feeds frame index of camera view, captured ms after the first frame.
+func (l *syncLog) add(view, ms int, index int64) {
+	meta := frame.New(index)
+	meta.Timestamp = l.base.Add(time.Duration(ms) * time.Millisecond)
+	groups, dropped := l.s.add(viewFrame{view: view, img: gocv.NewMat(), meta: meta})
+	for _, g := range groups {
+		line := "group"
+		for i, f := range g {
+			sep := " "
+			if i > 0 {
+				sep = "+"
+			}
+			line += fmt.Sprintf("%s%d", sep, f.meta.Index)
+			f.img.Close()
+		}
+		l.out = append(l.out, line)
+	}
+	for _, f := range dropped {
+		l.out = append(l.out, fmt.Sprintf("drop %d", f.meta.Index))
+		f.img.Close()
+	}
+}
+
+func (l *syncLog) expect(t *testing.T, want ...string) {
+	t.Helper()
+	if fmt.Sprint(l.out) != fmt.Sprint(want) {
+		t.Errorf("syncer gave %q, want %q", l.out, want)
+	}
+	l.out = nil
+}
+
+func TestSyncer(t *testing.T) {
+	t.Run("pairs frames within the tolerance", func(t *testing.T) {
+		l := &syncLog{s: newSyncer(2, 50*time.Millisecond), base: time.Now()}
+		defer l.s.close()
+
+		l.add(0, 0, 1)
+		l.expect(t) // waits for the other camera
+		l.add(1, 10, 2)
+		l.expect(t, "group 1+2")
+
+		l.add(0, 33, 3)
+		l.add(0, 66, 4)
+		l.add(1, 70, 5)
+		l.expect(t, "group 4+5", "drop 3") // the nearest frame wins
+	})
+
+	t.Run("drops frames too far apart", func(t *testing.T) {
+		l := &syncLog{s: newSyncer(2, 50*time.Millisecond), base: time.Now()}
+		defer l.s.close()
+
+		l.add(0, 0, 1)
+		l.add(1, 200, 2)
+		l.expect(t, "drop 1")
+		l.add(0, 210, 3)
+		l.expect(t, "group 3+2")
+	})
+
+	t.Run("groups are in camera order", func(t *testing.T) {
+		l := &syncLog{s: newSyncer(3, 50*time.Millisecond), base: time.Now()}
+		defer l.s.close()
+
+		l.add(2, 0, 1)
+		l.add(0, 5, 2)
+		l.add(1, 3, 3)
+		l.expect(t, "group 2+3+1")
+	})
+
+	t.Run("bounds the queue of a camera running alone", func(t *testing.T) {
+		l := &syncLog{s: newSyncer(2, 50*time.Millisecond), base: time.Now()}
+		defer l.s.close()
+
+		for i := 0; i < 9; i++ {
+			l.add(0, i, int64(i))
+		}
+		l.expect(t, "drop 0")
+	})
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"github.com/Elliot727/gocvkit/camera"
+	"github.com/Elliot727/gocvkit/config"
+)
+
+// Option customises an App at construction time.
+//
+// Options are applied after the configuration has been loaded but before any
+// resources (camera, window, recorder) are opened, so they can override what
+// the TOML file says. A hot reload of the file applies them again, so the
+// override lasts.
+type Option func(*App)
+
+// WithHeadless runs the App without a display window.
+// It is the programmatic equivalent of `[app] headless = true`.
+func WithHeadless() Option {
+	return func(a *App) {
+		a.Config.App.Headless = true
+	}
+}
+
+// WithoutSignalHandling stops Run and RunContext from installing their own
+// SIGINT/SIGTERM handler. Use it when the App is embedded in a larger program
+// that owns the process lifecycle and cancels the context passed to RunContext.
+func WithoutSignalHandling() Option {
+	return func(a *App) {
+		a.ignoreSignals = true
+	}
+}
+
+// WithConfigWatch hot-reloads the pipeline whenever the TOML file at path
+// changes. New enables it automatically for the file it loaded.
+func WithConfigWatch(path string) Option {
+	return func(a *App) {
+		a.configPath = path
+	}
+}
+
+// WithDevice captures from the webcam with the given device index.
+// The other [camera] settings, such as width and fps, are kept.
+func WithDevice(id int) Option {
+	return func(a *App) {
+		a.Config.Camera.Type = "device"
+		a.Config.Camera.DeviceID = id
+		a.Config.Camera.File = ""
+		a.Config.Camera.URL = ""
+	}
+}
+
+// WithFile reads frames from a video file instead of a webcam.
+// The other [camera] settings, such as loop and speed, are kept.
+func WithFile(path string) Option {
+	return func(a *App) {
+		a.Config.Camera.Type = "file"
+		a.Config.Camera.File = path
+		a.Config.Camera.URL = ""
+	}
+}
+
+// WithURL reads frames from a network stream (rtsp://, http://, ...),
+// reconnecting whenever it drops. The other [camera] settings, such as the
+// reconnect delays, are kept.
+func WithURL(url string) Option {
+	return func(a *App) {
+		a.Config.Camera.Type = "url"
+		a.Config.Camera.URL = url
+		a.Config.Camera.File = ""
+	}
+}
+
+// WithSource reads frames from src instead of opening the [camera] source.
+// The App takes ownership and closes src on Close.
+func WithSource(src camera.Source) Option {
+	return func(a *App) {
+		a.Camera = src
+	}
+}
+
+// WithSteps replaces the pipeline with the given steps, in order.
+func WithSteps(steps ...config.StepConfig) Option {
+	return func(a *App) {
+		a.Config.Pipeline.Steps = steps
+	}
+}
+
+// WithRecording enables recording of the processed frames to output.
+func WithRecording(output string) Option {
+	return func(a *App) {
+		a.Config.App.Record = true
+		a.Config.App.Output = output
+	}
+}
+
+// WithStream serves the processed frames as MJPEG on the given port and path.
+func WithStream(port int, path string) Option {
+	return func(a *App) {
+		a.Config.Stream.Enabled = true
+		a.Config.Stream.Port = port
+		a.Config.Stream.Path = path
+	}
+}
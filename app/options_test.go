@@ -0,0 +1,28 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Elliot727/gocvkit/config"
+)
+
+// The source options pick the source and keep the rest of [camera].
+func TestSourceOptionsKeepCameraSettings(t *testing.T) {
+	a := &App{Config: &config.Config{}}
+	a.Config.Camera = config.CameraConfig{URL: "rtsp://cam/1", Width: 1280, Height: 720, FPS: 25, Loop: true}
+
+	WithFile("clip.mp4")(a)
+	if c := a.Config.Camera; c.Type != "file" || c.File != "clip.mp4" || c.URL != "" || !c.Loop || c.Width != 1280 {
+		t.Errorf("WithFile: %+v", c)
+	}
+
+	WithDevice(2)(a)
+	if c := a.Config.Camera; c.Type != "device" || c.DeviceID != 2 || c.File != "" || c.FPS != 25 || c.Height != 720 {
+		t.Errorf("WithDevice: %+v", c)
+	}
+
+	WithURL("rtsp://cam/2")(a)
+	if c := a.Config.Camera; c.Type != "url" || c.URL != "rtsp://cam/2" || c.Width != 1280 {
+		t.Errorf("WithURL: %+v", c)
+	}
+}
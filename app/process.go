@@ -0,0 +1,235 @@
+package app
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/Elliot727/gocvkit/builder"
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/pipeline"
+
+	"gocv.io/x/gocv"
+)
+
+// buildPipelines builds the primary pipeline plus a.workers-1 replicas of it,
+// all reporting step timings to the app metrics.
+// A pipeline with a sequential-only step (see processor.Sequential) is never
+// replicated: its steps must see every frame in order on a single instance.
+func (a *App) buildPipelines(cfg *config.Config) (*pipeline.Pipeline, []*pipeline.Pipeline, error) {
+	workers := a.workers
+	p, err := builder.Build(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.SetObserver(a.metrics.observeStep)
+
+	if workers > 1 && p.Sequential() {
+		log.Printf("Pipeline has sequential-only steps; running on 1 worker instead of %d", workers)
+		return p, nil, nil
+	}
+
+	var replicas []*pipeline.Pipeline
+	for i := 1; i < workers; i++ {
+		r, err := builder.Build(cfg)
+		if err != nil {
+			closePipelines(p, replicas)
+			return nil, nil, err
+		}
+		r.SetObserver(a.metrics.observeStep)
+		replicas = append(replicas, r)
+	}
+	return p, replicas, nil
+}
+
+// closePipelines closes a primary pipeline and its replicas, printing one
+// performance report for all of them.
+func closePipelines(p *pipeline.Pipeline, replicas []*pipeline.Pipeline) {
+	p.Merge(replicas...)
+	p.Close()
+	for _, r := range replicas {
+		r.Close()
+	}
+}
+
+// replica returns the pipeline that worker i should use and its slot index.
+// Workers share a pipeline (guarded by the slot's lock) only while there are
+// fewer pipelines than workers. The caller must hold a.mu for reading.
+func (a *App) replica(i int) (*pipeline.Pipeline, int) {
+	slot := i % (1 + len(a.replicas))
+	if slot == 0 {
+		return a.Pipeline, 0
+	}
+	return a.replicas[slot-1], slot
+}
+
+// process runs every frame through the pipeline and sends the results, in
+// capture order, to results, which is full according to bp. It closes results
+// when frames is exhausted or ctx is cancelled.
+func (a *App) process(ctx context.Context, frames <-chan result, results chan result, bp backpressure) {
+	defer close(results)
+	// Drain whatever the reader queued before it noticed cancellation.
+	defer func() {
+		for in := range frames {
+			in.img.Close()
+		}
+	}()
+
+	if a.workers > 1 {
+		a.processParallel(ctx, frames, results, bp)
+		return
+	}
+
+	for in := range frames {
+		out := gocv.NewMat()
+		meta := in.meta
+
+		a.mu.RLock()
+		cfg := a.Config
+		err := a.Pipeline.RunMeta(meta, in.img, &out)
+		var taps map[string]gocv.Mat
+		if err == nil {
+			taps = a.copyTaps(a.Pipeline)
+		}
+		a.mu.RUnlock()
+
+		in.img.Close() // We are done with the input frame
+
+		if err != nil {
+			out.Close()
+			log.Printf("Pipeline error: %v", err)
+			a.dropped(meta, "error")
+			continue
+		}
+
+		if !bp.send(ctx, results, result{img: out, meta: meta, cfg: cfg, taps: taps, pos: in.pos, seek: in.seek}) {
+			return
+		}
+	}
+}
+
+// processParallel spreads frames round-robin over a.workers goroutines, each
+// running its own pipeline replica, and re-orders their output by frame index.
+// When the current pipeline is sequential-only, every frame goes to worker 0
+// so ordering is preserved; after a reload to such a pipeline, the frames
+// already handed to other workers are processed before the next one.
+func (a *App) processParallel(ctx context.Context, frames <-chan result, results chan result, bp backpressure) {
+	// Frames are numbered again as they are dispatched: frame indices have
+	// gaps when the backpressure policy drops frames, and the re-ordering
+	// needs an unbroken sequence.
+	type job struct {
+		seq int64
+		in  result
+	}
+	type finished struct {
+		seq int64
+		res result
+		ok  bool // ok is false when the pipeline failed and the frame was dropped
+	}
+
+	queues := make([]chan job, a.workers)
+	done := make(chan finished, a.workers)
+	slots := make([]sync.Mutex, a.workers)
+	var running sync.WaitGroup // running counts the jobs dispatched but not yet through a pipeline
+
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan job, 1)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := range queues[i] {
+				out := gocv.NewMat()
+
+				a.mu.RLock()
+				cfg := a.Config
+				p, slot := a.replica(i)
+				slots[slot].Lock()
+				err := p.RunMeta(j.in.meta, j.in.img, &out)
+				var taps map[string]gocv.Mat
+				if err == nil {
+					taps = a.copyTaps(p)
+				}
+				slots[slot].Unlock()
+				a.mu.RUnlock()
+				running.Done()
+
+				j.in.img.Close()
+
+				if err != nil {
+					out.Close()
+					log.Printf("Pipeline error: %v", err)
+					a.dropped(j.in.meta, "error")
+					done <- finished{seq: j.seq}
+					continue
+				}
+				done <- finished{seq: j.seq, res: result{img: out, meta: j.in.meta, cfg: cfg, taps: taps, pos: j.in.pos, seek: j.in.seek}, ok: true}
+			}
+		}(i)
+	}
+
+	// Re-order: hold results back until every earlier frame has been emitted.
+	reordered := make(chan struct{})
+	go func() {
+		defer close(reordered)
+		pending := make(map[int64]finished)
+		var next int64
+		for f := range done {
+			pending[f.seq] = f
+			for {
+				f, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				if !f.ok {
+					continue
+				}
+				bp.send(ctx, results, f.res)
+			}
+		}
+		for _, f := range pending {
+			if f.ok {
+				f.res.close()
+			}
+		}
+	}()
+
+	var seq int64
+	wasParallel := false
+dispatch:
+	for in := range frames {
+		a.mu.RLock()
+		parallel := len(a.replicas) > 0
+		a.mu.RUnlock()
+
+		w := 0
+		if parallel {
+			w = int(seq % int64(a.workers))
+		} else if wasParallel {
+			// A reload made the pipeline sequential-only. Frames still queued
+			// to the other workers would now run on the single pipeline out of
+			// order with worker 0's, so let them through first.
+			running.Wait()
+		}
+		wasParallel = parallel
+
+		running.Add(1)
+		select {
+		case queues[w] <- job{seq: seq, in: in}:
+			seq++
+		case <-ctx.Done():
+			running.Done()
+			in.img.Close()
+			break dispatch
+		}
+	}
+
+	for _, q := range queues {
+		close(q)
+	}
+	wg.Wait()
+	close(done)
+	<-reordered
+}
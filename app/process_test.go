@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Elliot727/gocvkit/camera"
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/frame"
+	"github.com/Elliot727/gocvkit/processor"
+
+	_ "github.com/Elliot727/gocvkit/processor/core"
+
+	"gocv.io/x/gocv"
+)
+
+// indexTag tags every frame with the index it saw.
+type indexTag struct{}
+
+func (indexTag) Process(src gocv.Mat, dst *gocv.Mat) error {
+	src.CopyTo(dst)
+	return nil
+}
+
+func (t indexTag) ProcessMeta(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat) error {
+	meta.SetTag("seen", meta.Index)
+	return t.Process(src, dst)
+}
+
+func init() {
+	processor.Register("IndexTag", &indexTag{})
+}
+
+// What a step puts in the metadata reaches the callback with the frame it
+// was made for, past the steps that only see pixels.
+func TestMetaReachesCallback(t *testing.T) {
+	gen, err := camera.NewGenerator(camera.GeneratorOptions{Width: 64, Height: 48, Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.Pipeline.Workers = 2
+	a, err := NewFromConfig(cfg, WithSource(gen), WithHeadless(), WithoutSignalHandling(),
+		WithSteps(config.StepConfig{Name: "IndexTag"}, config.StepConfig{Name: "Grayscale"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	n := 0
+	err = a.RunFrames(context.Background(), func(img *gocv.Mat, meta *frame.Meta) {
+		n++
+		if v, ok := meta.Tag("seen"); !ok || v != meta.Index {
+			t.Errorf("frame %d: tag seen = %v, want %d", meta.Index, v, meta.Index)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 10 {
+		t.Errorf("callback ran %d times, want 10", n)
+	}
+}
+
+// Workers finish frames out of order; the callback must still see them in
+// capture order, each one processed.
+func TestParallelWorkersKeepOrder(t *testing.T) {
+	gen, err := camera.NewGenerator(camera.GeneratorOptions{Width: 64, Height: 48, Frames: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.Pipeline.Workers = 4
+	a, err := NewFromConfig(cfg, WithSource(gen), WithHeadless(), WithoutSignalHandling(),
+		WithSteps(config.StepConfig{Name: "Grayscale"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	var next int64
+	err = a.RunFrames(context.Background(), func(img *gocv.Mat, meta *frame.Meta) {
+		if meta.Index != next {
+			t.Errorf("got frame %d, want %d", meta.Index, next)
+		}
+		next = meta.Index + 1
+		if img.Channels() != 1 {
+			t.Errorf("frame %d has %d channels, want 1", meta.Index, img.Channels())
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != 20 {
+		t.Errorf("callback stopped after frame %d, want 19", next-1)
+	}
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/frame"
+)
+
+// backpressure decides what happens when one of the frame queues of
+// RunFrames (camera → pipeline → outputs) is full. It is fixed when Run
+// starts; a hot reload changes it on the next Run.
+type backpressure struct {
+	drop    bool              // drop discards the oldest queued frame instead of waiting for room
+	depth   int               // depth is the capacity of each queue
+	dropped func(*frame.Meta) // dropped is called for every frame discarded to make room
+}
+
+// backpressure returns the policy of cfg, the configuration Run started with.
+func (a *App) backpressure(cfg *config.Config) backpressure {
+	return backpressure{
+		drop:    cfg.Pipeline.Backpressure != config.BackpressureBlock,
+		depth:   max(cfg.Pipeline.QueueDepth, 1),
+		dropped: func(m *frame.Meta) { a.dropped(m, "queue") },
+	}
+}
+
+// dropped counts a frame that will never reach the outputs and notes it in
+// the sidecars of the recordings it was meant for. Safe to call from any goroutine.
+func (a *App) dropped(meta *frame.Meta, reason string) {
+	a.metrics.dropped.With(reason).Inc()
+	for _, rc := range a.recordings {
+		if rc.source != config.SourceInput {
+			rc.rec.NoteDropped(meta, reason)
+		}
+	}
+}
+
+// queue makes a frame queue of the configured depth.
+func (b backpressure) queue() chan result {
+	return make(chan result, b.depth)
+}
+
+// send queues r on ch. It reports false, after releasing r, if ctx is
+// cancelled first.
+func (b backpressure) send(ctx context.Context, ch chan result, r result) bool {
+	if !b.drop {
+		select {
+		case ch <- r:
+			return true
+		case <-ctx.Done():
+			r.close()
+			return false
+		}
+	}
+
+	for {
+		select {
+		case ch <- r:
+			return true
+		case <-ctx.Done():
+			r.close()
+			return false
+		default:
+		}
+
+		// Full: make room by discarding the oldest frame, unless the
+		// consumer just took it.
+		select {
+		case old := <-ch:
+			old.close()
+			b.dropped(old.meta)
+		default:
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Elliot727/gocvkit/frame"
+
+	"gocv.io/x/gocv"
+)
+
+func testResult(index int64) result {
+	return result{img: gocv.NewMat(), meta: frame.New(index)}
+}
+
+// fill sends frames 0..n-1 to a queue nobody reads, then returns the indexes
+// left in it and those bp dropped.
+func fill(t *testing.T, bp backpressure, n int) (queued, dropped []int64) {
+	bp.dropped = func(m *frame.Meta) { dropped = append(dropped, m.Index) }
+	ch := bp.queue()
+	for i := 0; i < n; i++ {
+		if !bp.send(context.Background(), ch, testResult(int64(i))) {
+			t.Fatalf("send(%d) = false", i)
+		}
+	}
+	close(ch)
+	for r := range ch {
+		queued = append(queued, r.meta.Index)
+		r.close()
+	}
+	return queued, dropped
+}
+
+func TestBackpressureDropOldest(t *testing.T) {
+	queued, dropped := fill(t, backpressure{drop: true, depth: 3}, 5)
+	if !reflect.DeepEqual(queued, []int64{2, 3, 4}) || !reflect.DeepEqual(dropped, []int64{0, 1}) {
+		t.Errorf("queued %v, dropped %v; want the newest 3 kept", queued, dropped)
+	}
+
+	// Nothing is dropped while there is room.
+	queued, dropped = fill(t, backpressure{drop: true, depth: 4}, 2)
+	if len(queued) != 2 || dropped != nil {
+		t.Errorf("queued %v, dropped %v; want both queued", queued, dropped)
+	}
+}
+
+func TestBackpressureLatestOnly(t *testing.T) {
+	queued, dropped := fill(t, backpressure{drop: true, depth: 1}, 4)
+	if !reflect.DeepEqual(queued, []int64{3}) || len(dropped) != 3 {
+		t.Errorf("queued %v, dropped %v; want only frame 3 left", queued, dropped)
+	}
+}
+
+func TestBackpressureBlock(t *testing.T) {
+	bp := backpressure{depth: 1, dropped: func(m *frame.Meta) {
+		t.Errorf("block dropped frame %d", m.Index)
+	}}
+	ch := bp.queue()
+	bp.send(context.Background(), ch, testResult(0))
+
+	// A full queue waits for the consumer...
+	sent := make(chan bool)
+	go func() { sent <- bp.send(context.Background(), ch, testResult(1)) }()
+	select {
+	case <-sent:
+		t.Fatal("send did not wait for room")
+	case <-time.After(50 * time.Millisecond):
+	}
+	r := <-ch
+	r.close()
+	if !<-sent {
+		t.Fatal("send = false once there was room")
+	}
+	r = <-ch
+	if r.meta.Index != 1 {
+		t.Errorf("queued frame %d, want 1", r.meta.Index)
+	}
+	r.close()
+
+	// ...or gives up when cancelled.
+	bp.send(context.Background(), ch, testResult(2))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if bp.send(ctx, ch, testResult(3)) {
+		t.Error("send = true on a full queue after cancel")
+	}
+	r = <-ch
+	r.close()
+}
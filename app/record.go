@@ -0,0 +1,241 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/frame"
+	"github.com/Elliot727/gocvkit/pipeline"
+	"github.com/Elliot727/gocvkit/recorder"
+
+	"gocv.io/x/gocv"
+)
+
+// recording is one [record] output: a recorder and the frames it takes.
+type recording struct {
+	source string // source is config.SourceInput, config.SourceOutput or a tap name
+	rec    *recorder.Recorder
+	retry  backoff // retry holds back writes after the recorder failed
+}
+
+// retryAfter is how long a recorder that failed is left alone before the
+// next write is tried.
+const retryAfter = 10 * time.Second
+
+// backoff holds back writes to a recorder that failed, so that a broken
+// output is logged once rather than on every frame, and does not use up a %N
+// number per frame trying to open a new file.
+type backoff struct {
+	until  time.Time // until is when the next write is tried
+	failed bool      // failed is set from the first failure until a write succeeds
+}
+
+// write calls fn for the recorder named what, unless a recent failure holds
+// it back. It logs when writes start failing and when they work again.
+func (b *backoff) write(what string, fn func() error) {
+	now := time.Now()
+	if now.Before(b.until) {
+		return
+	}
+	if err := fn(); err != nil {
+		if !b.failed {
+			log.Printf("❌ %s failed, retrying every %v: %v", what, retryAfter, err)
+		}
+		b.failed = true
+		b.until = now.Add(retryAfter)
+		return
+	}
+	if b.failed {
+		log.Printf("✅ %s recovered", what)
+		b.failed = false
+	}
+}
+
+// write records img, unless the recording failed recently.
+func (rc *recording) write(img gocv.Mat, meta *frame.Meta) {
+	rc.retry.write("Recording "+rc.source, func() error { return rc.rec.WriteMeta(img, meta) })
+}
+
+// openRecorders creates the recorders of cfg.Recordings and, with [clips]
+// and [snapshots], the clip recorder and the snapshot writer, and tries out
+// the [record] format of the recorders in use.
+//
+// In a multi-camera app the cameras record their own input, output and taps;
+// the App itself records only the output and taps of the [sync] pipeline.
+func (a *App) openRecorders(cfg *config.Config) error {
+	multi := len(cfg.Cameras) > 0
+	if cfg.App.Record && (!multi || cfg.Sync.Enabled) {
+		var syncTaps []string
+		if multi {
+			syncTaps = syncConfig(cfg).Taps()
+		}
+		for _, o := range cfg.Recordings() {
+			if multi && o.Source == config.SourceInput {
+				continue // each camera records its own input
+			}
+			if multi && o.Source != config.SourceOutput && !slices.Contains(syncTaps, o.Source) {
+				continue // a tap of the cameras' pipelines
+			}
+
+			rec := recorder.NewRecorder(o.Path)
+			rec.SetFormat(recorder.Format{Codec: o.Codec, Container: o.Container, Quality: o.Quality})
+			if err := rec.Check(); err != nil {
+				return fmt.Errorf("record %s: %w", o.Path, err)
+			}
+			rec.SetRotation(recorder.Rotation{
+				Interval: o.SegmentTime.D(),
+				MaxSize:  int64(o.SegmentSize),
+				KeepSize: int64(o.MaxTotalSize),
+				KeepAge:  o.MaxAge.D(),
+			})
+
+			a.recordings = append(a.recordings, recording{source: o.Source, rec: rec})
+			switch o.Source {
+			case config.SourceInput:
+			case config.SourceOutput:
+				if a.Recorder == nil {
+					a.Recorder = rec
+				}
+			default:
+				if !slices.Contains(a.taps, o.Source) {
+					a.taps = append(a.taps, o.Source)
+				}
+			}
+		}
+	}
+
+	format := recorder.Format{
+		Codec:     cfg.Record.Codec,
+		Container: cfg.Record.Container,
+		Quality:   cfg.Record.Quality,
+	}
+	if a.Recorder == nil {
+		// Nothing records the processed frames; keep an idle Recorder for
+		// callers that write to it themselves.
+		output := cfg.App.Output
+		if output == "" {
+			output = config.DefaultOutput
+		}
+		a.Recorder = recorder.NewRecorder(output)
+		a.Recorder.SetFormat(format)
+	}
+
+	if cfg.Clips.Enabled {
+		a.Clips = recorder.NewClipper(cfg.Clips.Output, cfg.Clips.Pre.D(), cfg.Clips.Post.D(), cfg.Clips.Events...)
+		a.Clips.SetFormat(format)
+		if err := a.Clips.Check(); err != nil {
+			return fmt.Errorf("clips: %w", err)
+		}
+	}
+
+	// Unsynchronised multi-camera apps have no frames of their own: the
+	// cameras save the snapshots.
+	if sc := cfg.Snapshots; sc.Enabled && (!multi || cfg.Sync.Enabled) {
+		a.Snapshots = recorder.NewSnapshotter(sc.Output)
+		a.Snapshots.SetInterval(sc.Interval.D())
+		a.Snapshots.SetQuality(sc.Quality)
+		a.Snapshots.SetMaxFiles(sc.MaxFiles)
+	}
+	return nil
+}
+
+// configureRecorder applies the frame rate and the [app] settings of cfg,
+// the configuration Run started with, to the recorders before the first
+// frame is written.
+func (a *App) configureRecorder(cfg *config.Config, fps float64) {
+	for _, rc := range a.recordings {
+		rc.rec.SetFPS(fps)
+		rc.rec.SetSidecar(cfg.App.RecordMeta)
+		rc.rec.SetConfig(cfg)
+	}
+	if a.Clips != nil {
+		a.Clips.SetFPS(fps)
+		a.Clips.SetSidecar(cfg.App.RecordMeta)
+		a.Clips.SetConfig(cfg)
+	}
+}
+
+// recorders returns Recorder and the recorders of every recording, once each.
+// Recorder is nil only while the App is being constructed.
+func (a *App) recorders() []*recorder.Recorder {
+	var recs []*recorder.Recorder
+	if a.Recorder != nil {
+		recs = append(recs, a.Recorder)
+	}
+	for _, rc := range a.recordings {
+		if rc.rec != a.Recorder {
+			recs = append(recs, rc.rec)
+		}
+	}
+	return recs
+}
+
+// recordsInput reports whether any recording takes the frames fresh from
+// the camera.
+func (a *App) recordsInput() bool {
+	return slices.ContainsFunc(a.recordings, func(rc recording) bool { return rc.source == config.SourceInput })
+}
+
+// inputQueue returns the policy of the queue between the reader and the
+// "input" recordings: that of bp, but counting what it drops as "record" and
+// noting it in the input recordings' sidecars only. The frames dropped
+// before the pipeline are still in the input recordings.
+func (a *App) inputQueue(bp backpressure) backpressure {
+	bp.dropped = func(meta *frame.Meta) {
+		a.metrics.dropped.With("record").Inc()
+		for _, rc := range a.recordings {
+			if rc.source == config.SourceInput {
+				rc.rec.NoteDropped(meta, "record")
+			}
+		}
+	}
+	return bp
+}
+
+// recordInputs writes the frames queued on inputs to the "input" recordings
+// and releases them, until inputs is closed. It runs on a goroutine of its
+// own, so a slow encoder or disk never holds up the camera.
+func (a *App) recordInputs(inputs <-chan result) {
+	for r := range inputs {
+		for i := range a.recordings {
+			if rc := &a.recordings[i]; rc.source == config.SourceInput {
+				rc.write(r.img, r.meta)
+			}
+		}
+		r.close()
+	}
+}
+
+// recordOutput writes a processed frame, or its taps, to the other recordings.
+func (a *App) recordOutput(img gocv.Mat, r result) {
+	for i := range a.recordings {
+		rc := &a.recordings[i]
+		switch rc.source {
+		case config.SourceInput:
+		case config.SourceOutput:
+			rc.write(img, r.meta)
+		default:
+			if t, ok := r.taps[rc.source]; ok {
+				rc.write(t, r.meta)
+			}
+		}
+	}
+}
+
+// copyTaps copies the taps that recordings need out of p, which reuses them
+// for its next frame. The caller must hold p.
+func (a *App) copyTaps(p *pipeline.Pipeline) map[string]gocv.Mat {
+	if len(a.taps) == 0 {
+		return nil
+	}
+	taps := make(map[string]gocv.Mat, len(a.taps))
+	for _, name := range a.taps {
+		if m, ok := p.Tap(name); ok {
+			taps[name] = m.Clone()
+		}
+	}
+	return taps
+}
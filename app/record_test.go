@@ -0,0 +1,169 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Elliot727/gocvkit/camera"
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/frame"
+
+	"gocv.io/x/gocv"
+)
+
+func TestBackoff(t *testing.T) {
+	var b backoff
+	calls := 0
+	fail := func() error { calls++; return errors.New("disk full") }
+
+	b.write("Recording output", fail)
+	b.write("Recording output", fail)
+	if calls != 1 {
+		t.Fatalf("a failed recorder was written %d times right away, want 1", calls)
+	}
+	if !b.failed || time.Until(b.until) <= 0 {
+		t.Fatalf("after a failure: failed %v, retry in %v", b.failed, time.Until(b.until))
+	}
+
+	// Once the pause is over the next frame is written again.
+	b.until = time.Now().Add(-time.Millisecond)
+	b.write("Recording output", func() error { calls++; return nil })
+	if calls != 2 || b.failed {
+		t.Errorf("after the pause: %d calls, failed %v; want 2, false", calls, b.failed)
+	}
+}
+
+// The sidecar of a recording accounts for every frame read: each one is
+// either written, with its index, or noted as dropped. A hot reload is noted
+// with the first frame it made.
+func TestRecordSidecar(t *testing.T) {
+	dir := t.TempDir()
+	gen, err := camera.NewGenerator(camera.GeneratorOptions{Width: 64, Height: 48, Frames: 20, FPS: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.App.RecordMeta = true
+	cfg.Pipeline.Backpressure = config.BackpressureLatestOnly
+	cfg.Pipeline.QueueDepth = 1
+	a, err := NewFromConfig(cfg, WithSource(gen), WithHeadless(), WithoutSignalHandling(),
+		WithRecording(filepath.Join(dir, "out.mp4")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	// The callback is slower than the camera, so frames are dropped; the
+	// first one reloads the config, which the frames read after it carry.
+	reloaded := false
+	err = a.RunFrames(context.Background(), func(*gocv.Mat, *frame.Meta) {
+		if !reloaded {
+			reloaded = true
+			next := *a.Config
+			next.Stream.Quality = 50
+			if err := a.reload(&next); err != nil {
+				t.Errorf("reload: %v", err)
+			}
+		}
+		time.Sleep(25 * time.Millisecond)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Close() // finishes the sidecar
+
+	type line struct {
+		Type   string `json:"type"`
+		Index  int64  `json:"index"`
+		Reason string `json:"reason"`
+		Config *struct {
+			Stream struct {
+				Quality int `json:"quality"`
+			} `json:"stream"`
+		} `json:"config"`
+	}
+	names, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if len(names) != 1 {
+		t.Fatalf("sidecars %v, want 1", names)
+	}
+	f, err := os.Open(names[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines []line
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var l line
+		if err := json.Unmarshal(sc.Bytes(), &l); err != nil {
+			t.Fatalf("sidecar line %q: %v", sc.Text(), err)
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) < 2 {
+		t.Fatalf("sidecar has %d lines", len(lines))
+	}
+
+	// nextFrame returns the index of the first frame line after line i.
+	nextFrame := func(i int) int64 {
+		for _, l := range lines[i+1:] {
+			if l.Type == "" {
+				return l.Index
+			}
+		}
+		t.Fatalf("no frame after line %d", i)
+		return -1
+	}
+
+	if h := lines[0]; h.Type != "config" || h.Config == nil || h.Config.Stream.Quality != 75 || h.Index != nextFrame(0) {
+		t.Errorf("first line %+v, want the config with quality 75 and the first frame's index", h)
+	}
+	seen := make(map[int64]string)
+	reloads, drops := 0, 0
+	last := int64(-1)
+	for i, l := range lines[1:] {
+		switch l.Type {
+		case "":
+			if l.Index <= last {
+				t.Errorf("frame %d written after frame %d", l.Index, last)
+			}
+			last = l.Index
+		case "reload":
+			reloads++
+			if l.Config == nil || l.Config.Stream.Quality != 50 || l.Index != nextFrame(i+1) {
+				t.Errorf("reload line %+v, want quality 50 and the index of the next frame", l)
+			}
+			continue
+		case "dropped":
+			drops++
+			if l.Reason != "queue" {
+				t.Errorf("frame %d dropped for %q, want queue", l.Index, l.Reason)
+			}
+		default:
+			t.Errorf("unexpected line %+v", l)
+			continue
+		}
+		if prev, ok := seen[l.Index]; ok {
+			t.Errorf("frame %d both %q and %q", l.Index, prev, l.Type)
+		}
+		seen[l.Index] = l.Type
+	}
+	if reloads != 1 {
+		t.Errorf("%d reload lines, want 1", reloads)
+	}
+	if drops == 0 {
+		t.Error("no dropped frames noted")
+	}
+	for i := int64(0); i < 20; i++ {
+		if _, ok := seen[i]; !ok {
+			t.Errorf("frame %d is neither written nor noted as dropped", i)
+		}
+	}
+}
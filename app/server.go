@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// startServer serves the MJPEG stream (one per camera in a multi-camera app), the metrics endpoint and the control
+// API on the [stream] port. It does nothing when all of them are disabled.
+func (a *App) startServer() {
+	cfg := a.Config
+	if !cfg.Stream.Enabled && !cfg.Metrics.Enabled && !cfg.API.Enabled {
+		return
+	}
+
+	mux := http.NewServeMux()
+	if cfg.Stream.Enabled {
+		// Unsynchronised multi-camera apps have no combined stream, only one per camera.
+		if len(a.views) == 0 || cfg.Sync.Enabled {
+			handleStream(mux, cfg.Stream.Path, a)
+		}
+		for _, v := range a.views {
+			handleStream(mux, v.Config.Stream.Path, v)
+		}
+	}
+	if cfg.Metrics.Enabled {
+		mux.Handle(cfg.Metrics.Path, a.metrics.reg)
+	}
+	if cfg.API.Enabled {
+		a.registerAPI(mux, cfg.API.Path)
+	}
+
+	a.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Stream.Port),
+		Handler: mux,
+	}
+	go func() {
+		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("HTTP server error: %v", err)
+		}
+	}()
+}
+
+// handleStream serves a's MJPEG stream at path and its metadata at "<path>/meta".
+func handleStream(mux *http.ServeMux, path string, a *App) {
+	mux.Handle(path, a.Streamer)
+	mux.Handle(strings.TrimSuffix(path, "/")+"/meta", a.Streamer.MetaHandler())
+}
@@ -9,20 +9,82 @@ import (
 	"fmt"
 
 	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/internal/suggest"
+	"github.com/Elliot727/gocvkit/pipeline"
 	"github.com/Elliot727/gocvkit/processor"
 )
 
+// Build constructs a ready-to-run pipeline from the config, including the
+// named-buffer routing (input, tap, with) of each step.
+// Returns an error if any step fails to build or refers to a buffer that
+// has not been written by an earlier step.
+func Build(cfg *config.Config) (*pipeline.Pipeline, error) {
+	steps, err := BuildPipeline(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	stages, err := route(cfg.Pipeline.Steps, steps)
+	if err != nil {
+		for _, step := range steps {
+			step.Close()
+		}
+		return nil, err
+	}
+
+	return pipeline.NewStaged(stages), nil
+}
+
+// route pairs each built step with its buffer routing and checks that every
+// input and with refers to the source frame or a tap written earlier.
+func route(scs []config.StepConfig, steps []processor.Step) ([]pipeline.Stage, error) {
+	stages := make([]pipeline.Stage, len(steps))
+	known := map[string]bool{pipeline.Source: true}
+
+	for i, sc := range scs {
+		if sc.Input != "" && !known[sc.Input] {
+			return nil, fmt.Errorf("pipeline step %d (%s): input %q is not %q or an earlier tap", i, sc.Name, sc.Input, pipeline.Source)
+		}
+
+		_, merger := steps[i].(processor.Merger)
+		switch {
+		case merger && sc.With == "":
+			return nil, fmt.Errorf("pipeline step %d (%s): merge step needs a 'with' buffer", i, sc.Name)
+		case !merger && sc.With != "":
+			return nil, fmt.Errorf("pipeline step %d (%s): 'with' is only valid on merge steps", i, sc.Name)
+		case sc.With != "" && !known[sc.With]:
+			return nil, fmt.Errorf("pipeline step %d (%s): with %q is not %q or an earlier tap", i, sc.Name, sc.With, pipeline.Source)
+		}
+
+		if sc.Tap == pipeline.Source {
+			return nil, fmt.Errorf("pipeline step %d (%s): tap name %q is reserved", i, sc.Name, pipeline.Source)
+		}
+		if sc.Tap != "" {
+			known[sc.Tap] = true
+		}
+
+		stages[i] = pipeline.Stage{Step: steps[i], Input: sc.Input, Tap: sc.Tap, With: sc.With}
+	}
+
+	return stages, nil
+}
+
 // BuildPipeline constructs the ordered list of processing steps from the config.
 // Returns an error if any step name is unknown or its factory fails.
+// The steps carry no routing; use Build for pipelines with taps and merges.
 func BuildPipeline(cfg *config.Config) ([]processor.Step, error) {
 	var steps []processor.Step
 
 	for i, sc := range cfg.Pipeline.Steps {
 		factory, ok := processor.Get(sc.Name)
 		if !ok {
+			if s := suggest.Closest(sc.Name, processor.List()); s != "" {
+				return nil, fmt.Errorf("pipeline step %d: unknown processor %q (did you mean %q?)", i, sc.Name, s)
+			}
 			return nil, fmt.Errorf("pipeline step %d: unknown processor %q", i, sc.Name)
 		}
 
+		sc.Permissive = cfg.App.Permissive
 		step, err := factory(sc)
 		if err != nil {
 			return nil, fmt.Errorf("pipeline step %d (%s): %w", i, sc.Name, err)
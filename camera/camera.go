@@ -1,24 +1,90 @@
 // Package camera provides a clean, unified wrapper around gocv.VideoCapture
-// that works identically for webcam devices and video files.
+// that works identically for webcam devices, video files and network streams.
 //
 // Usage from config:
 //
-//	device_id = 0          → opens default webcam
-//	file = "video.mp4"     → opens video file (device_id is ignored)
+//	device_id = 0              → opens default webcam
+//	file = "video.mp4"         → opens video file (device_id is ignored)
+//	url = "rtsp://cam/stream"  → opens a network stream and reconnects when it drops
+//	images = "frames/*.png"    → reads an image sequence (glob or directory) in sorted order
 //
-// The wrapper hides the difference between the two sources and adds
+// The wrapper hides the difference between the sources and adds
 // convenient helpers (Width, Height, FPS).
 package camera
 
 import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
 	"gocv.io/x/gocv"
 )
 
-// Camera represents an open video source (webcam or file).
+// OpenCV capture properties for the FFmpeg backend, which gocv has no names for.
+const (
+	propOpenTimeoutMSec gocv.VideoCaptureProperties = 53 // CAP_PROP_OPEN_TIMEOUT_MSEC
+	propReadTimeoutMSec gocv.VideoCaptureProperties = 54 // CAP_PROP_READ_TIMEOUT_MSEC
+)
+
+// Camera represents an open video source (webcam, file or network stream).
 type Camera struct {
 	device int                // device is the camera device ID when using webcam
 	file   string             // file is the path to video file when using file input
+	url    string             // url is the network stream address when using a stream
+	opts   StreamOptions      // opts controls reconnecting when using a stream
 	cap    *gocv.VideoCapture // cap is the underlying video capture instance
+	seq    *sequence          // seq is the image sequence when reading a folder of frames
+
+	start int  // start is the first frame index of a file's playback range
+	end   int  // end is the index after the last frame of the range (0 = end of file)
+	loop  bool // loop restarts a file at start after the end
+
+	mu      sync.Mutex    // mu guards cap while a stream is being reopened
+	reading bool          // reading is set while a device or stream read runs without mu; Close leaves cap to it
+	closed  chan struct{} // closed interrupts a reconnect backoff or a read in progress on Close
+	once    sync.Once
+
+	// OnEvent, if set, is called for every disconnect and reconnect of a stream.
+	OnEvent func(Event)
+}
+
+// StreamOptions controls how a network stream is read and reopened.
+type StreamOptions struct {
+	ReconnectDelay    time.Duration // ReconnectDelay is the wait before the first reconnect attempt
+	ReconnectMaxDelay time.Duration // ReconnectMaxDelay caps the backoff, which doubles after every failed attempt
+	MaxRetries        int           // MaxRetries is the number of attempts before Read gives up (0 = retry forever)
+	ReadTimeout       time.Duration // ReadTimeout bounds opening the stream and waiting for each frame
+}
+
+// EventType says what happened to a network stream.
+type EventType int
+
+const (
+	Disconnected EventType = iota // Disconnected: a read failed or timed out
+	Reconnected                   // Reconnected: the stream was reopened
+	GaveUp                        // GaveUp: MaxRetries attempts failed; Read now returns false
+)
+
+// String returns the event name for logs.
+func (t EventType) String() string {
+	switch t {
+	case Disconnected:
+		return "disconnected"
+	case Reconnected:
+		return "reconnected"
+	case GaveUp:
+		return "gave_up"
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event describes a disconnect or reconnect of a network stream.
+type Event struct {
+	Type    EventType
+	URL     string
+	Attempt int   // Attempt is the reconnect attempt number (0 for Disconnected)
+	Err     error // Err is the last open error, if any
 }
 
 // NewCamera opens either a webcam (by device ID) or a video file.
@@ -41,31 +107,230 @@ func NewCamera(device int, file string) (*Camera, error) {
 		device: device,
 		file:   file,
 		cap:    cap,
+		closed: make(chan struct{}),
 	}, nil
 }
 
+// NewStream opens a network stream (rtsp://, http://, ...). Unlike a device
+// or file, a stream that drops is reopened with exponential backoff; Read
+// only returns false once opts.MaxRetries attempts have failed.
+// The first open is not retried: an unreachable URL is reported straight away.
+func NewStream(url string, opts StreamOptions) (*Camera, error) {
+	c := &Camera{
+		url:    url,
+		opts:   opts,
+		closed: make(chan struct{}),
+	}
+
+	cap, err := c.openStream()
+	if err != nil {
+		return nil, err
+	}
+	c.cap = cap
+	return c, nil
+}
+
+// openStream opens c.url with the configured timeouts.
+func (c *Camera) openStream() (*gocv.VideoCapture, error) {
+	var params []gocv.VideoCaptureProperties
+	if ms := c.opts.ReadTimeout.Milliseconds(); ms > 0 {
+		params = append(params,
+			propOpenTimeoutMSec, gocv.VideoCaptureProperties(ms),
+			propReadTimeoutMSec, gocv.VideoCaptureProperties(ms),
+		)
+	}
+
+	var cap *gocv.VideoCapture
+	var err error
+	if len(params) > 0 {
+		cap, err = gocv.VideoCaptureFileWithAPIParams(c.url, gocv.VideoCaptureAny, params)
+	} else {
+		cap, err = gocv.VideoCaptureFile(c.url)
+	}
+	if err == nil && !cap.IsOpened() {
+		err = fmt.Errorf("could not open stream %s", c.url)
+	}
+	if err != nil {
+		if cap != nil {
+			cap.Close()
+		}
+		return nil, err
+	}
+	return cap, nil
+}
+
 // Read reads the next frame into the provided Mat.
-// Returns false if no more frames are available (e.g. end of file or camera disconnected).
+// Returns false if no more frames are available (e.g. end of file or camera
+// disconnected). A network stream is reopened first, and only reports false
+// after giving up or when the camera is closed.
+//
+// Reading a device or stream can block (up to the read timeout), so the
+// other methods, Close included, do not wait for it.
 func (c *Camera) Read(frame *gocv.Mat) bool {
-	return c.cap.Read(frame)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seq != nil {
+		return c.seq.read(frame, c.closed)
+	}
+	if c.file != "" {
+		return c.readFile(frame)
+	}
+	if c.url == "" {
+		return c.readUnlocked(frame)
+	}
+
+	for {
+		select {
+		case <-c.closed:
+			return false
+		default:
+		}
+		if c.readUnlocked(frame) && !frame.Empty() {
+			return true
+		}
+		if !c.reconnect() {
+			return false
+		}
+	}
+}
+
+// readUnlocked reads from c.cap with c.mu released, so that a slow device or
+// stream does not hold up Close and the getters. Called with c.mu held, which
+// it holds again on return. Should Close run meanwhile, it leaves the capture
+// to be released here, and the read reports false.
+func (c *Camera) readUnlocked(frame *gocv.Mat) bool {
+	cap := c.cap
+	if cap == nil {
+		return false
+	}
+
+	c.reading = true
+	c.mu.Unlock()
+	ok := cap.Read(frame)
+	c.mu.Lock()
+	c.reading = false
+
+	select {
+	case <-c.closed:
+		c.release()
+		return false
+	default:
+		return ok
+	}
 }
 
-// Close releases the underlying VideoCapture.
+// reconnect reopens the stream with exponential backoff. It is called with
+// c.mu held and releases it while waiting, so Close is never blocked for long.
+func (c *Camera) reconnect() bool {
+	c.notify(Event{Type: Disconnected, URL: c.url})
+	c.release()
+
+	delay := c.opts.ReconnectDelay
+	var lastErr error
+	for attempt := 1; c.opts.MaxRetries <= 0 || attempt <= c.opts.MaxRetries; attempt++ {
+		c.mu.Unlock()
+		select {
+		case <-time.After(delay):
+		case <-c.closed:
+			c.mu.Lock()
+			return false
+		}
+		c.mu.Lock()
+
+		cap, err := c.openStream()
+		if err == nil {
+			c.cap = cap
+			c.notify(Event{Type: Reconnected, URL: c.url, Attempt: attempt})
+			return true
+		}
+		lastErr = err
+
+		delay *= 2
+		if c.opts.ReconnectMaxDelay > 0 && delay > c.opts.ReconnectMaxDelay {
+			delay = c.opts.ReconnectMaxDelay
+		}
+	}
+
+	c.notify(Event{Type: GaveUp, URL: c.url, Attempt: c.opts.MaxRetries, Err: lastErr})
+	return false
+}
+
+// notify logs the event and passes it to OnEvent.
+func (c *Camera) notify(e Event) {
+	switch e.Type {
+	case Disconnected:
+		log.Printf("📡 Stream %s disconnected, reconnecting...", e.URL)
+	case Reconnected:
+		log.Printf("📡 Stream %s reconnected (attempt %d)", e.URL, e.Attempt)
+	case GaveUp:
+		log.Printf("📡 Stream %s: giving up after %d attempts: %v", e.URL, e.Attempt, e.Err)
+	}
+	if c.OnEvent != nil {
+		c.OnEvent(e)
+	}
+}
+
+// Close releases the underlying VideoCapture. A Read in progress is told to
+// stop and releases it when its read returns.
 func (c *Camera) Close() {
-	c.cap.Close()
+	c.once.Do(func() { close(c.closed) })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.reading {
+		c.release()
+	}
+}
+
+// release closes cap. Called with c.mu held.
+func (c *Camera) release() {
+	if c.cap != nil {
+		c.cap.Close()
+		c.cap = nil
+	}
+}
+
+// get reads a capture property, or 0 while a stream is reconnecting.
+func (c *Camera) get(prop gocv.VideoCaptureProperties) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.seq != nil {
+		return c.seq.get(prop)
+	}
+	if c.cap == nil {
+		return 0
+	}
+	return c.cap.Get(prop)
+}
+
+// Filename returns the file the last frame was read from when reading an
+// image sequence, or "" for other sources.
+func (c *Camera) Filename() string {
+	if c.seq == nil {
+		return ""
+	}
+	return c.seq.current()
+}
+
+// Timestamp returns the media position of the last frame read: the
+// presentation timestamp of a file or stream as reported by the backend
+// (usually 0 for webcams), or index/fps in a paced image sequence.
+func (c *Camera) Timestamp() time.Duration {
+	return time.Duration(c.get(gocv.VideoCapturePosMsec) * float64(time.Millisecond))
 }
 
 // Width returns the frame width of the video source.
 func (c *Camera) Width() int {
-	return int(c.cap.Get(gocv.VideoCaptureFrameWidth))
+	return int(c.get(gocv.VideoCaptureFrameWidth))
 }
 
 // Height returns the frame height of the video source.
 func (c *Camera) Height() int {
-	return int(c.cap.Get(gocv.VideoCaptureFrameHeight))
+	return int(c.get(gocv.VideoCaptureFrameHeight))
 }
 
 // FPS returns the frames per second of the video source (may be 0.0 for some webcams).
 func (c *Camera) FPS() float64 {
-	return c.cap.Get(gocv.VideoCaptureFPS)
+	return c.get(gocv.VideoCaptureFPS)
 }
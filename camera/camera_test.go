@@ -0,0 +1,228 @@
+package camera
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// testJPEG returns the JPEG frame sent by the test streams.
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+
+	img := gocv.NewMatWithSize(120, 160, gocv.MatTypeCV8UC3)
+	defer img.Close()
+	gocv.Rectangle(&img, image.Rect(40, 30, 120, 90), color.RGBA{255, 255, 255, 0}, -1)
+	buf, err := gocv.IMEncode(gocv.JPEGFileExt, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Close()
+	return append([]byte(nil), buf.GetBytes()...)
+}
+
+// mjpeg serves jpeg as an MJPEG stream until stop is closed, then keeps the
+// connection open without sending anything, like a camera that hangs.
+// A nil stop streams until the client hangs up.
+func mjpeg(jpeg []byte, stop <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=frame")
+		tick := time.NewTicker(40 * time.Millisecond)
+		defer tick.Stop()
+		for {
+			select {
+			case <-stop:
+				<-r.Context().Done() // stall until the client hangs up
+				return
+			case <-r.Context().Done():
+				return
+			case <-tick.C:
+				fmt.Fprintf(w, "--frame\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", len(jpeg))
+				w.Write(jpeg)
+				w.Write([]byte("\r\n"))
+				w.(http.Flusher).Flush()
+			}
+		}
+	})
+}
+
+// stallingStream serves an MJPEG stream that stalls once stop is closed.
+func stallingStream(t *testing.T, stop <-chan struct{}) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(mjpeg(testJPEG(t), stop))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestReadDoesNotBlockClose(t *testing.T) {
+	stop := make(chan struct{})
+	srv := stallingStream(t, stop)
+
+	cam, err := NewStream(srv.URL+"/stream.mjpg", StreamOptions{
+		ReadTimeout: 10 * time.Second,
+		MaxRetries:  1,
+	})
+	if err != nil {
+		t.Skipf("OpenCV cannot open an MJPEG stream over HTTP here: %v", err)
+	}
+
+	frame := gocv.NewMat()
+	defer frame.Close()
+	if !cam.Read(&frame) {
+		t.Fatal("Read() = false before the stream stalled")
+	}
+
+	// Stall the stream and read until Read hangs.
+	close(stop)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for cam.Read(&frame) {
+		}
+	}()
+	time.Sleep(500 * time.Millisecond)
+
+	within := func(name string, d time.Duration, f func()) {
+		t.Helper()
+		returned := make(chan struct{})
+		go func() {
+			f()
+			close(returned)
+		}()
+		select {
+		case <-returned:
+		case <-time.After(d):
+			t.Fatalf("%s blocked behind a stalled Read", name)
+		}
+	}
+	within("Width", time.Second, func() { cam.Width() })
+	within("FPS", time.Second, func() { cam.FPS() })
+	within("Close", time.Second, cam.Close)
+
+	// The hung read ends at the read timeout at the latest, then sees Close.
+	srv.CloseClientConnections()
+	select {
+	case <-done:
+	case <-time.After(15 * time.Second):
+		t.Fatal("Read did not return after Close")
+	}
+}
+
+func TestCloseIdempotent(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	srv := stallingStream(t, stop)
+
+	cam, err := NewStream(srv.URL+"/stream.mjpg", StreamOptions{ReadTimeout: 5 * time.Second})
+	if err != nil {
+		t.Skipf("OpenCV cannot open an MJPEG stream over HTTP here: %v", err)
+	}
+	cam.Close()
+	cam.Close()
+
+	frame := gocv.NewMat()
+	defer frame.Close()
+	if cam.Read(&frame) {
+		t.Error("Read() = true after Close")
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	jpeg := testJPEG(t)
+	srv := httptest.NewServer(mjpeg(jpeg, nil))
+	addr := srv.Listener.Addr().String()
+	defer srv.Close()
+
+	cam, err := NewStream(srv.URL+"/stream.mjpg", StreamOptions{
+		ReconnectDelay:    50 * time.Millisecond,
+		ReconnectMaxDelay: 200 * time.Millisecond,
+		MaxRetries:        50,
+		ReadTimeout:       5 * time.Second,
+	})
+	if err != nil {
+		t.Skipf("OpenCV cannot open an MJPEG stream over HTTP here: %v", err)
+	}
+	defer cam.Close()
+
+	var mu sync.Mutex
+	var events []Event
+	disconnected := make(chan struct{})
+	var once sync.Once
+	cam.OnEvent = func(e Event) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+		if e.Type == Disconnected {
+			once.Do(func() { close(disconnected) })
+		}
+	}
+
+	frame := gocv.NewMat()
+	defer frame.Close()
+	if !cam.Read(&frame) {
+		t.Fatal("Read() = false before the server stopped")
+	}
+
+	// Stop the server, and start it again on the same address once the
+	// camera has noticed and a reconnect attempt or two have failed.
+	srv.CloseClientConnections()
+	srv.Close()
+	restarted := make(chan *httptest.Server, 1)
+	go func() {
+		<-disconnected
+		time.Sleep(300 * time.Millisecond)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Errorf("restarting the server: %v", err)
+			restarted <- nil
+			return
+		}
+		srv := &httptest.Server{Listener: l, Config: &http.Server{Handler: mjpeg(jpeg, nil)}}
+		srv.Start()
+		restarted <- srv
+	}()
+
+	// Read returns the frames OpenCV still holds, then blocks reconnecting
+	// until the server is back, and goes on with the new connection.
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		if !cam.Read(&frame) {
+			t.Fatal("Read() = false instead of reconnecting")
+		}
+		mu.Lock()
+		n := len(events)
+		mu.Unlock()
+		if n >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the camera did not reconnect")
+		}
+	}
+	if srv := <-restarted; srv != nil {
+		defer func() {
+			srv.CloseClientConnections()
+			srv.Close()
+		}()
+	}
+	if frame.Empty() {
+		t.Error("Read() after reconnecting left the frame empty")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 || events[0].Type != Disconnected || events[1].Type != Reconnected {
+		t.Fatalf("events = %v, want Disconnected then Reconnected", events)
+	}
+	if events[1].Attempt < 1 {
+		t.Errorf("Reconnected on attempt %d, want 1 or more", events[1].Attempt)
+	}
+}
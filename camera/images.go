@@ -0,0 +1,174 @@
+package camera
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// imageExts are the extensions picked up when images names a directory.
+var imageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".bmp": true, ".tif": true, ".tiff": true, ".webp": true,
+}
+
+// sequence reads a sorted list of image files as if they were video frames.
+type sequence struct {
+	files  []string
+	fps    float64 // fps paces Read; 0 reads as fast as the pipeline consumes
+	loop   bool    // loop restarts from the first file after the last
+	width  int
+	height int
+
+	mu   sync.Mutex
+	next int       // next is the index of the file Read returns next
+	last int       // last is the index of the file of the last frame read
+	cur  string    // cur is the file of the last frame read
+	due  time.Time // due is when the next frame may be returned
+}
+
+// NewImages opens an image sequence. pattern is either a glob
+// ("frames/*.png") or a directory, in which case every image file inside it
+// is used. Files are read in sorted (lexical) order, so zero-pad frame numbers.
+// fps paces the frames (0 = as fast as possible); loop starts over at the end.
+func NewImages(pattern string, fps float64, loop bool) (*Camera, error) {
+	files, err := listImages(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	// Probe the first frame for the sequence size.
+	first := gocv.IMRead(files[0], gocv.IMReadColor)
+	defer first.Close()
+	if first.Empty() {
+		return nil, fmt.Errorf("could not read image %s", files[0])
+	}
+
+	return &Camera{
+		seq: &sequence{
+			files:  files,
+			fps:    fps,
+			loop:   loop,
+			width:  first.Cols(),
+			height: first.Rows(),
+		},
+		closed: make(chan struct{}),
+	}, nil
+}
+
+// listImages expands pattern into a sorted list of image files.
+func listImages(pattern string) ([]string, error) {
+	var files []string
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() && imageExts[strings.ToLower(filepath.Ext(e.Name()))] {
+				files = append(files, filepath.Join(pattern, e.Name()))
+			}
+		}
+	} else {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = matches
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no images found for %q", pattern)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// read loads the next image into frame, waiting for its turn when paced.
+// Files that cannot be decoded are skipped.
+func (s *sequence) read(frame *gocv.Mat, closed <-chan struct{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for skipped := 0; skipped < len(s.files); skipped++ {
+		if s.next >= len(s.files) {
+			if !s.loop {
+				return false
+			}
+			s.next = 0
+		}
+		file := s.files[s.next]
+		s.next++
+
+		img := gocv.IMRead(file, gocv.IMReadColor)
+		if img.Empty() {
+			img.Close()
+			log.Printf("⚠️  Skipping unreadable image %s", file)
+			continue
+		}
+
+		if s.fps > 0 {
+			if wait := time.Until(s.due); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-closed:
+					img.Close()
+					return false
+				}
+			}
+			s.due = time.Now().Add(time.Duration(float64(time.Second) / s.fps))
+		}
+
+		img.CopyTo(frame)
+		img.Close()
+		s.cur = file
+		s.last = s.next - 1
+		return true
+	}
+	return false // nothing readable left
+}
+
+func (s *sequence) seek(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next = max(0, min(n, len(s.files)-1))
+}
+
+func (s *sequence) position() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
+
+func (s *sequence) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
+func (s *sequence) get(prop gocv.VideoCaptureProperties) float64 {
+	switch prop {
+	case gocv.VideoCaptureFrameWidth:
+		return float64(s.width)
+	case gocv.VideoCaptureFrameHeight:
+		return float64(s.height)
+	case gocv.VideoCaptureFPS:
+		return s.fps
+	case gocv.VideoCaptureFrameCount:
+		return float64(len(s.files))
+	case gocv.VideoCapturePosMsec:
+		// A paced sequence has a timeline like a video: frame n sits at n/fps.
+		if s.fps > 0 {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return float64(s.last) * 1000 / s.fps
+		}
+	}
+	return 0
+}
@@ -0,0 +1,119 @@
+package camera
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// writeImages writes a 16x12 image for each name into dir; the extension
+// picks the format.
+func writeImages(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		img := gocv.NewMatWithSize(12, 16, gocv.MatTypeCV8UC3)
+		ok := gocv.IMWrite(filepath.Join(dir, name), img)
+		img.Close()
+		if !ok {
+			t.Fatalf("could not write %s", name)
+		}
+	}
+}
+
+// readNames reads n frames from cam and returns the files they came from.
+func readNames(t *testing.T, cam *Camera, n int) []string {
+	t.Helper()
+	frame := gocv.NewMat()
+	defer frame.Close()
+
+	var names []string
+	for i := 0; i < n; i++ {
+		if !cam.Read(&frame) {
+			t.Fatalf("Read() = false after %d frames, want %d", i, n)
+		}
+		if frame.Cols() != 16 || frame.Rows() != 12 {
+			t.Fatalf("frame %d is %dx%d, want 16x12", i, frame.Cols(), frame.Rows())
+		}
+		names = append(names, filepath.Base(cam.Filename()))
+	}
+	return names
+}
+
+func TestImagesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeImages(t, dir, "frame_010.png", "frame_002.png", "frame_001.jpg")
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cam, err := NewImages(dir, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cam.Close()
+
+	if cam.Width() != 16 || cam.Height() != 12 {
+		t.Errorf("size = %dx%d, want 16x12", cam.Width(), cam.Height())
+	}
+	want := []string{"frame_001.jpg", "frame_002.png", "frame_010.png"}
+	got := readNames(t, cam, 3)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("read %v, want %v", got, want)
+		}
+	}
+
+	frame := gocv.NewMat()
+	defer frame.Close()
+	if cam.Read(&frame) {
+		t.Error("Read() = true after the last image")
+	}
+}
+
+func TestImagesGlobLoop(t *testing.T) {
+	dir := t.TempDir()
+	writeImages(t, dir, "b.png", "a.png", "skip.jpg")
+
+	cam, err := NewImages(filepath.Join(dir, "*.png"), 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cam.Close()
+
+	got := readNames(t, cam, 5)
+	want := []string{"a.png", "b.png", "a.png", "b.png", "a.png"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("read %v, want %v", got, want)
+		}
+	}
+}
+
+func TestImagesPaced(t *testing.T) {
+	dir := t.TempDir()
+	writeImages(t, dir, "0.png", "1.png", "2.png")
+
+	cam, err := NewImages(dir, 20, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cam.Close()
+
+	start := time.Now()
+	readNames(t, cam, 3)
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 frames at 20 fps took %v, want at least 100ms", elapsed)
+	}
+	if ts := cam.Timestamp(); ts != 100*time.Millisecond {
+		t.Errorf("Timestamp() = %v on the third frame, want 100ms", ts)
+	}
+}
+
+func TestImagesEmpty(t *testing.T) {
+	if _, err := NewImages(filepath.Join(t.TempDir(), "*.png"), 0, false); err == nil {
+		t.Error("NewImages accepted a glob that matches nothing")
+	}
+}
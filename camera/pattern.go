@@ -0,0 +1,265 @@
+package camera
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Elliot727/gocvkit/config"
+
+	"gocv.io/x/gocv"
+)
+
+// Patterns produced by the "pattern" source.
+const (
+	PatternBars         = "bars"         // PatternBars is SMPTE-style colour bars
+	PatternBox          = "box"          // PatternBox is a white box bouncing over black
+	PatternCheckerboard = "checkerboard" // PatternCheckerboard is a static black and white board
+	PatternNoise        = "noise"        // PatternNoise is uniform noise from a fixed seed
+)
+
+// GeneratorOptions configures a synthetic test-pattern source.
+type GeneratorOptions struct {
+	Pattern string  // Pattern is one of the Pattern* names (default "bars")
+	Width   int     // Width of the frames (default 640)
+	Height  int     // Height of the frames (default 480)
+	FPS     float64 // FPS paces the frames (0 = as fast as they are read)
+	Frames  int     // Frames ends the source after that many frames (0 = endless)
+	Seed    int64   // Seed makes the noise pattern reproducible
+	Counter bool    // Counter burns the frame number into every frame, as text and as a binary strip
+}
+
+// Generator is a Source that renders test patterns, so apps, pipelines and
+// recorders can be exercised without a camera (CI, benchmarks).
+// Frame n is identical on every run with the same options.
+type Generator struct {
+	opts GeneratorOptions
+	base gocv.Mat // base is the pre-rendered static part of the pattern
+
+	mu     sync.Mutex
+	n      int       // n is the number of frames returned so far
+	due    time.Time // due is when the next frame may be returned
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewGenerator creates a test-pattern source.
+func NewGenerator(opts GeneratorOptions) (*Generator, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = PatternBars
+	}
+	if opts.Width <= 0 {
+		opts.Width = 640
+	}
+	if opts.Height <= 0 {
+		opts.Height = 480
+	}
+	if opts.FPS < 0 || opts.Frames < 0 {
+		return nil, fmt.Errorf("pattern fps and frames must be >= 0")
+	}
+	if opts.Counter && opts.Width < 32 {
+		return nil, fmt.Errorf("pattern counter needs a width of at least 32, got %d", opts.Width)
+	}
+
+	g := &Generator{opts: opts, closed: make(chan struct{})}
+	switch opts.Pattern {
+	case PatternBars:
+		g.base = renderBars(opts.Width, opts.Height)
+	case PatternCheckerboard:
+		g.base = renderCheckerboard(opts.Width, opts.Height)
+	case PatternBox:
+		g.base = gocv.NewMatWithSizeFromScalar(gocv.NewScalar(0, 0, 0, 0), opts.Height, opts.Width, gocv.MatTypeCV8UC3)
+	case PatternNoise:
+		g.base = gocv.NewMat()
+	default:
+		return nil, fmt.Errorf("unknown pattern %q (use %q, %q, %q or %q)",
+			opts.Pattern, PatternBars, PatternBox, PatternCheckerboard, PatternNoise)
+	}
+	return g, nil
+}
+
+// Read renders the next frame into frame, waiting for its turn when paced.
+func (g *Generator) Read(frame *gocv.Mat) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.opts.Frames > 0 && g.n >= g.opts.Frames {
+		return false
+	}
+
+	if g.opts.FPS > 0 {
+		if wait := time.Until(g.due); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-g.closed:
+				return false
+			}
+		}
+		g.due = time.Now().Add(time.Duration(float64(time.Second) / g.opts.FPS))
+	}
+
+	select {
+	case <-g.closed:
+		return false
+	default:
+	}
+
+	w, h := g.opts.Width, g.opts.Height
+	switch g.opts.Pattern {
+	case PatternBox:
+		g.base.CopyTo(frame)
+		gocv.Rectangle(frame, boxAt(g.n, w, h), color.RGBA{255, 255, 255, 0}, -1)
+	case PatternNoise:
+		// Seed per frame so frame n does not depend on how many were read before.
+		buf := make([]byte, w*h*3)
+		rand.New(rand.NewSource(g.opts.Seed + int64(g.n))).Read(buf)
+		noise, err := gocv.NewMatFromBytes(h, w, gocv.MatTypeCV8UC3, buf)
+		if err != nil {
+			return false
+		}
+		noise.CopyTo(frame)
+		noise.Close()
+	default:
+		g.base.CopyTo(frame)
+	}
+
+	if g.opts.Counter {
+		burnCounter(frame, g.n)
+	}
+	g.n++
+	return true
+}
+
+// Close stops the generator; a paced Read in progress returns false.
+func (g *Generator) Close() {
+	g.once.Do(func() { close(g.closed) })
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.base.Close()
+}
+
+// Timestamp returns the position of the last frame on the generator's
+// timeline, (n-1)/fps; always 0 when unpaced.
+func (g *Generator) Timestamp() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.opts.FPS <= 0 || g.n == 0 {
+		return 0
+	}
+	return time.Duration(float64(g.n-1) / g.opts.FPS * float64(time.Second))
+}
+
+// Width returns the frame width.
+func (g *Generator) Width() int { return g.opts.Width }
+
+// Height returns the frame height.
+func (g *Generator) Height() int { return g.opts.Height }
+
+// FPS returns the configured frame rate (0 when unpaced).
+func (g *Generator) FPS() float64 { return g.opts.FPS }
+
+// renderBars draws SMPTE-style bars: seven 75% bars, a reversed castellation
+// strip, and a bottom row of -I, white, +Q and black.
+func renderBars(w, h int) gocv.Mat {
+	img := gocv.NewMatWithSize(h, w, gocv.MatTypeCV8UC3)
+
+	top := []color.RGBA{
+		{191, 191, 191, 0}, {191, 191, 0, 0}, {0, 191, 191, 0}, {0, 191, 0, 0},
+		{191, 0, 191, 0}, {191, 0, 0, 0}, {0, 0, 191, 0},
+	}
+	mid := []color.RGBA{
+		{0, 0, 191, 0}, {19, 19, 19, 0}, {191, 0, 191, 0}, {19, 19, 19, 0},
+		{0, 191, 191, 0}, {19, 19, 19, 0}, {191, 191, 191, 0},
+	}
+	bottom := []color.RGBA{{0, 33, 76, 0}, {255, 255, 255, 0}, {50, 0, 106, 0}, {19, 19, 19, 0}}
+
+	y1, y2 := h*2/3, h*3/4
+	for i := range top {
+		x0, x1 := i*w/len(top), (i+1)*w/len(top)
+		gocv.Rectangle(&img, image.Rect(x0, 0, x1, y1), top[i], -1)
+		gocv.Rectangle(&img, image.Rect(x0, y1, x1, y2), mid[i], -1)
+	}
+	for i := range bottom {
+		x0, x1 := i*w/len(bottom), (i+1)*w/len(bottom)
+		gocv.Rectangle(&img, image.Rect(x0, y2, x1, h), bottom[i], -1)
+	}
+	return img
+}
+
+// renderCheckerboard draws 8 squares down the frame, as many across as fit.
+func renderCheckerboard(w, h int) gocv.Mat {
+	img := gocv.NewMatWithSizeFromScalar(gocv.NewScalar(0, 0, 0, 0), h, w, gocv.MatTypeCV8UC3)
+	size := max(h/8, 1)
+	white := color.RGBA{255, 255, 255, 0}
+	for y := 0; y < h; y += size {
+		for x := 0; x < w; x += size {
+			if (x/size+y/size)%2 == 0 {
+				gocv.Rectangle(&img, image.Rect(x, y, x+size, y+size), white, -1)
+			}
+		}
+	}
+	return img
+}
+
+// boxAt returns where the moving box is in frame n: it travels diagonally
+// and bounces off the edges, four pixels per frame.
+func boxAt(n, w, h int) image.Rectangle {
+	size := max(min(w, h)/8, 1)
+	x := bounce(n*4, w-size)
+	y := bounce(n*4, h-size)
+	return image.Rect(x, y, x+size, y+size)
+}
+
+// bounce folds a distance travelled into a position that ping-pongs over [0, span].
+func bounce(d, span int) int {
+	if span <= 0 {
+		return 0
+	}
+	d %= 2 * span
+	if d > span {
+		return 2*span - d
+	}
+	return d
+}
+
+// burnCounter writes n as text in the top-left corner and as a 32-bit strip
+// of square blocks (most significant bit first, white = 1) along the
+// bottom-left edge, so tests can decode the frame number from the pixels.
+// The blocks are 8x8, or width/32 wide on frames narrower than 256 pixels.
+func burnCounter(img *gocv.Mat, n int) {
+	text := fmt.Sprintf("#%d", n)
+	gocv.PutText(img, text, image.Pt(11, 31), gocv.FontHersheyPlain, 2, color.RGBA{0, 0, 0, 0}, 4)
+	gocv.PutText(img, text, image.Pt(10, 30), gocv.FontHersheyPlain, 2, color.RGBA{255, 255, 255, 0}, 2)
+
+	cell := min(8, img.Cols()/32)
+	y := img.Rows() - cell
+	for bit := 0; bit < 32; bit++ {
+		c := color.RGBA{0, 0, 0, 0}
+		if uint32(n)&(1<<(31-bit)) != 0 {
+			c = color.RGBA{255, 255, 255, 0}
+		}
+		gocv.Rectangle(img, image.Rect(bit*cell, y, (bit+1)*cell, y+cell), c, -1)
+	}
+}
+
+func init() {
+	Register("pattern", func(cfg config.CameraConfig) (Source, error) {
+		g, err := NewGenerator(GeneratorOptions{
+			Pattern: cfg.Pattern,
+			Width:   cfg.Width,
+			Height:  cfg.Height,
+			FPS:     cfg.FPS,
+			Frames:  cfg.Frames,
+			Seed:    cfg.Seed,
+			Counter: cfg.Counter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return g, nil
+	})
+}
@@ -0,0 +1,73 @@
+package camera
+
+import (
+	"testing"
+
+	"gocv.io/x/gocv"
+)
+
+// readCounter decodes the bit strip that burnCounter draws along the
+// bottom-left edge of img.
+func readCounter(img gocv.Mat) int {
+	cell := min(8, img.Cols()/32)
+	y := img.Rows() - cell/2 - 1
+	n := 0
+	for bit := 0; bit < 32; bit++ {
+		n <<= 1
+		if img.GetVecbAt(y, bit*cell+cell/2)[0] > 127 {
+			n |= 1
+		}
+	}
+	return n
+}
+
+func TestGeneratorCounter(t *testing.T) {
+	for _, width := range []int{640, 64} {
+		g, err := NewGenerator(GeneratorOptions{Pattern: PatternCheckerboard, Width: width, Height: 48, Frames: 6, Counter: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		img := gocv.NewMat()
+		n := 0
+		for g.Read(&img) {
+			if got := readCounter(img); got != n {
+				t.Errorf("width %d: frame %d carries counter %d", width, n, got)
+			}
+			n++
+		}
+		if n != 6 {
+			t.Errorf("width %d: read %d frames, want 6", width, n)
+		}
+		img.Close()
+		g.Close()
+	}
+
+	if _, err := NewGenerator(GeneratorOptions{Width: 16, Counter: true}); err == nil {
+		t.Error("NewGenerator accepted a counter on a 16 pixel wide frame")
+	}
+}
+
+func TestGeneratorNoiseReproducible(t *testing.T) {
+	frame := func(n int) gocv.Mat {
+		g, err := NewGenerator(GeneratorOptions{Pattern: PatternNoise, Width: 32, Height: 24, Seed: 7})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer g.Close()
+		img := gocv.NewMat()
+		for i := 0; i <= n; i++ {
+			g.Read(&img)
+		}
+		return img
+	}
+
+	a, b := frame(2), frame(2)
+	defer a.Close()
+	defer b.Close()
+	diff := gocv.NewMat()
+	defer diff.Close()
+	gocv.AbsDiff(a, b, &diff)
+	if s := diff.Sum(); s.Val1+s.Val2+s.Val3 != 0 {
+		t.Error("frame 2 differs between two generators with the same seed")
+	}
+}
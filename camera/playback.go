@@ -0,0 +1,124 @@
+package camera
+
+import (
+	"fmt"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// Seeker is an optional extension of Source for sources that can jump to a
+// frame: video files and image sequences. The App uses it for the seek and
+// single-step keys.
+type Seeker interface {
+	// Seek moves to frame index n (clamped to the playable range), so the next
+	// Read returns that frame.
+	Seek(n int) error
+	// Position returns the index of the frame the next Read returns.
+	Position() int
+}
+
+// Playback restricts and loops the frames read from a video file.
+// Times are converted to frame indices using the file's frame rate; when
+// both a time and a frame index are set, the frame index wins.
+type Playback struct {
+	Loop       bool          // Loop jumps back to the start after the end
+	Start      time.Duration // Start skips to this time
+	End        time.Duration // End stops at this time (0 = end of file)
+	StartFrame int           // StartFrame skips to this frame index
+	EndFrame   int           // EndFrame stops before this frame index (0 = end of file)
+}
+
+// NewFile opens a video file with playback controls.
+func NewFile(path string, pb Playback) (*Camera, error) {
+	c, err := NewCamera(0, path)
+	if err != nil || c == nil {
+		return nil, err
+	}
+
+	fps := c.cap.Get(gocv.VideoCaptureFPS)
+	c.start = pb.StartFrame
+	if c.start == 0 && pb.Start > 0 {
+		c.start = int(pb.Start.Seconds() * fps)
+	}
+	c.end = pb.EndFrame
+	if c.end == 0 && pb.End > 0 {
+		c.end = int(pb.End.Seconds() * fps)
+	}
+	c.loop = pb.Loop
+
+	if c.end > 0 && c.end <= c.start {
+		c.Close()
+		return nil, fmt.Errorf("%s: playback end (frame %d) must be after start (frame %d)", path, c.end, c.start)
+	}
+	if c.start > 0 {
+		c.cap.Set(gocv.VideoCapturePosFrames, float64(c.start))
+	}
+	return c, nil
+}
+
+// readFile reads the next frame of a video file, honouring the playback
+// range and looping. Called with c.mu held.
+func (c *Camera) readFile(frame *gocv.Mat) bool {
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.cap == nil {
+			return false // closed
+		}
+		inRange := c.end == 0 || c.position() < c.end
+		if inRange && c.cap.Read(frame) && !frame.Empty() {
+			return true
+		}
+		if !c.loop {
+			return false
+		}
+		c.cap.Set(gocv.VideoCapturePosFrames, float64(c.start))
+	}
+	return false // the range holds no readable frame
+}
+
+// position is the index of the next frame. Called with c.mu held.
+func (c *Camera) position() int {
+	return int(c.cap.Get(gocv.VideoCapturePosFrames))
+}
+
+// Seek moves a video file or image sequence to frame n.
+func (c *Camera) Seek(n int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case c.seq != nil:
+		c.seq.seek(n)
+		return nil
+	case c.file == "" || c.cap == nil:
+		return fmt.Errorf("source is not seekable")
+	}
+
+	// Clamp to the range; without an end or a known frame count (some
+	// containers report 0 or -1) only the start is enforced.
+	last := c.end - 1
+	if c.end <= 0 {
+		last = int(c.cap.Get(gocv.VideoCaptureFrameCount)) - 1
+	}
+	if last >= 0 {
+		n = min(n, last)
+	}
+	n = max(c.start, n)
+	c.cap.Set(gocv.VideoCapturePosFrames, float64(n))
+	return nil
+}
+
+// Position returns the index of the frame the next Read returns, or 0 for
+// sources without a position (devices, streams).
+func (c *Camera) Position() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case c.seq != nil:
+		return c.seq.position()
+	case c.file == "" || c.cap == nil:
+		return 0
+	}
+	return c.position()
+}
@@ -0,0 +1,132 @@
+package camera
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"gocv.io/x/gocv"
+)
+
+// Properties are capture settings requested from a webcam. Zero values and
+// nil pointers leave the driver's default in place. Drivers are free to
+// ignore or round any of them; Configure reports what actually took effect.
+type Properties struct {
+	Width        int      // Width is the requested frame width
+	Height       int      // Height is the requested frame height
+	FPS          float64  // FPS is the requested capture rate
+	FourCC       string   // FourCC is the pixel format, e.g. "MJPG" (often needed for high resolutions over USB)
+	AutoExposure *bool    // AutoExposure switches automatic exposure on or off
+	Exposure     *float64 // Exposure is the manual exposure, in driver units (usually needs AutoExposure = false)
+	Gain         *float64 // Gain is the sensor gain, in driver units
+	Focus        *float64 // Focus is the manual focus, in driver units (turns autofocus off)
+	BufferSize   int      // BufferSize is the number of frames the driver queues (1 = lowest latency)
+}
+
+// IsZero reports whether no property is requested.
+func (p Properties) IsZero() bool {
+	return p == Properties{}
+}
+
+// Configure applies p to a webcam through VideoCapture.Set, then reads the
+// values back, logs the settings in effect and warns about every requested
+// value the driver did not honour. With a zero p it only logs the settings.
+// It does nothing for other sources.
+func (c *Camera) Configure(p Properties) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cap == nil || c.file != "" || c.url != "" || c.seq != nil {
+		return
+	}
+
+	type request struct {
+		name string
+		prop gocv.VideoCaptureProperties
+		want float64
+	}
+	var reqs []request
+	add := func(name string, prop gocv.VideoCaptureProperties, want float64) {
+		reqs = append(reqs, request{name, prop, want})
+	}
+
+	// FOURCC first: many USB cameras only offer high resolutions as MJPG.
+	if p.FourCC != "" {
+		add("fourcc", gocv.VideoCaptureFOURCC, c.cap.ToCodec(p.FourCC))
+	}
+	if p.Width > 0 {
+		add("width", gocv.VideoCaptureFrameWidth, float64(p.Width))
+	}
+	if p.Height > 0 {
+		add("height", gocv.VideoCaptureFrameHeight, float64(p.Height))
+	}
+	if p.FPS > 0 {
+		add("fps", gocv.VideoCaptureFPS, p.FPS)
+	}
+	if p.BufferSize > 0 {
+		add("buffer_size", gocv.VideoCaptureBufferSize, float64(p.BufferSize))
+	}
+	if p.AutoExposure != nil {
+		add("auto_exposure", gocv.VideoCaptureAutoExposure, c.autoExposureValue(*p.AutoExposure))
+	}
+	if p.Exposure != nil {
+		add("exposure", gocv.VideoCaptureExposure, *p.Exposure)
+	}
+	if p.Gain != nil {
+		add("gain", gocv.VideoCaptureGain, *p.Gain)
+	}
+	if p.Focus != nil {
+		c.cap.Set(gocv.VideoCaptureAutoFocus, 0)
+		add("focus", gocv.VideoCaptureFocus, *p.Focus)
+	}
+
+	for _, r := range reqs {
+		c.cap.Set(r.prop, r.want)
+	}
+
+	// Read everything back only after all sets: a later property (e.g. fps)
+	// can make the driver renegotiate an earlier one (e.g. size).
+	for _, r := range reqs {
+		got := c.cap.Get(r.prop)
+		if math.Abs(got-r.want) > 0.01*math.Max(1, math.Abs(r.want)) {
+			log.Printf("⚠️  Camera did not honour %s = %s (using %s)", r.name, c.format(r.prop, r.want), c.format(r.prop, got))
+		}
+	}
+
+	log.Printf("📷 Camera %d: %dx%d @ %.4g fps, %s",
+		c.device,
+		int(c.cap.Get(gocv.VideoCaptureFrameWidth)),
+		int(c.cap.Get(gocv.VideoCaptureFrameHeight)),
+		c.cap.Get(gocv.VideoCaptureFPS),
+		c.format(gocv.VideoCaptureFOURCC, c.cap.Get(gocv.VideoCaptureFOURCC)),
+	)
+}
+
+// autoExposureValue maps on/off to the backend's convention: V4L2 uses
+// 0.75 (aperture priority) and 0.25 (manual), most others 1 and 0.
+func (c *Camera) autoExposureValue(on bool) float64 {
+	v4l2 := gocv.VideoCaptureAPI(c.cap.Get(gocv.VideoCaptureBackend)) == gocv.VideoCaptureV4L2
+	switch {
+	case v4l2 && on:
+		return 0.75
+	case v4l2:
+		return 0.25
+	case on:
+		return 1
+	}
+	return 0
+}
+
+// format renders a property value for logs, decoding FOURCC codes.
+func (c *Camera) format(prop gocv.VideoCaptureProperties, v float64) string {
+	if prop != gocv.VideoCaptureFOURCC {
+		return fmt.Sprintf("%g", v)
+	}
+	code := uint32(v)
+	var b strings.Builder
+	for i := 0; i < 4; i++ {
+		b.WriteByte(byte(code >> (8 * i)))
+	}
+	return fmt.Sprintf("FOURCC %q", strings.TrimRight(b.String(), "\x00"))
+}
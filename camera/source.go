@@ -0,0 +1,179 @@
+package camera
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/internal/suggest"
+
+	"github.com/BurntSushi/toml"
+	"gocv.io/x/gocv"
+)
+
+// Source is anything that produces frames: a webcam, a file, a network
+// stream, or a custom producer such as a ROS bridge or a test fixture.
+// Camera implements it for the built-in types.
+type Source interface {
+	// Read reads the next frame into the provided Mat.
+	// Returns false when no more frames will come (end of input or failure).
+	Read(frame *gocv.Mat) bool
+	// Close releases the source. A Read in progress should return false.
+	Close()
+	// Width and Height return the frame size, or 0 if unknown.
+	Width() int
+	Height() int
+	// FPS returns the native frame rate, or 0 if unknown (e.g. live sources).
+	FPS() float64
+}
+
+// Namer is an optional extension of Source for sources whose frames come
+// from named files. The name of the last frame read is tagged as "file"
+// on its metadata.
+type Namer interface {
+	Filename() string
+}
+
+// Timestamper is an optional extension of Source for sources that know where
+// in the media the last frame read sits: the presentation timestamp of a
+// video file or stream, or the frame's slot in a paced sequence. It is
+// stored as the frame's Position.
+type Timestamper interface {
+	Timestamp() time.Duration
+}
+
+// Factory opens a Source from the [camera] table.
+type Factory func(cfg config.CameraConfig) (Source, error)
+
+// sources is private to prevent external tampering, like the processor registry.
+var sources = make(map[string]Factory)
+
+// Register makes a source type available as `[camera] type = "<name>"`.
+// Call it from an init function. Custom settings arrive in cfg.Options
+// (the [camera.options] table); DecodeOptions unpacks them into a struct.
+func Register(name string, factory Factory) {
+	if factory == nil {
+		panic(fmt.Sprintf("camera.Register: %q has a nil Factory", name))
+	}
+	sources[name] = factory
+}
+
+// Open opens the source selected by cfg.Type. When Type is empty it is
+// inferred from the fields that are set: url, then images, then file,
+// then device_id.
+func Open(cfg config.CameraConfig) (Source, error) {
+	typ := cfg.Type
+	if typ == "" {
+		switch {
+		case cfg.URL != "":
+			typ = "url"
+		case cfg.Images != "":
+			typ = "images"
+		case cfg.File != "":
+			typ = "file"
+		default:
+			typ = "device"
+		}
+	}
+
+	factory, ok := sources[typ]
+	if !ok {
+		names := make([]string, 0, len(sources))
+		for name := range sources {
+			names = append(names, name)
+		}
+		if s := suggest.Closest(typ, names); s != "" {
+			return nil, fmt.Errorf("unknown camera type %q (did you mean %q?)", typ, s)
+		}
+		return nil, fmt.Errorf("unknown camera type %q", typ)
+	}
+	return factory(cfg)
+}
+
+// DecodeOptions decodes [camera.options] into v, a pointer to a struct with
+// toml tags, the same way pipeline step parameters are decoded.
+// Keys that match no field are an error.
+func DecodeOptions(opts map[string]interface{}, v interface{}) error {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(opts); err != nil {
+		return fmt.Errorf("camera options: %w", err)
+	}
+	md, err := toml.Decode(buf.String(), v)
+	if err != nil {
+		return fmt.Errorf("camera options: %w", err)
+	}
+
+	if keys := md.Undecoded(); len(keys) > 0 {
+		unknown := make([]string, len(keys))
+		for i, k := range keys {
+			unknown[i] = fmt.Sprintf("%q", k.String())
+		}
+		return fmt.Errorf("camera options: unknown keys %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// source converts a constructor result to a Source without producing a
+// non-nil interface holding a nil *Camera.
+func source(c *Camera, err error) (Source, error) {
+	if err != nil || c == nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func init() {
+	Register("device", func(cfg config.CameraConfig) (Source, error) {
+		c, err := NewCamera(cfg.DeviceID, "")
+		if err != nil || c == nil {
+			return nil, err
+		}
+		c.Configure(Properties{
+			Width:        cfg.Width,
+			Height:       cfg.Height,
+			FPS:          cfg.FPS,
+			FourCC:       cfg.FourCC,
+			AutoExposure: cfg.AutoExposure,
+			Exposure:     cfg.Exposure,
+			Gain:         cfg.Gain,
+			Focus:        cfg.Focus,
+			BufferSize:   cfg.BufferSize,
+		})
+		return c, nil
+	})
+	Register("file", func(cfg config.CameraConfig) (Source, error) {
+		if cfg.File == "" {
+			return nil, fmt.Errorf("camera type \"file\" needs a file")
+		}
+		return source(NewFile(cfg.File, Playback{
+			Loop:       cfg.Loop,
+			Start:      cfg.StartTime.D(),
+			End:        cfg.EndTime.D(),
+			StartFrame: cfg.StartFrame,
+			EndFrame:   cfg.EndFrame,
+		}))
+	})
+	Register("url", func(cfg config.CameraConfig) (Source, error) {
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("camera type \"url\" needs a url")
+		}
+		return source(NewStream(cfg.URL, StreamOptions{
+			ReconnectDelay:    cfg.ReconnectDelay.D(),
+			ReconnectMaxDelay: cfg.ReconnectMaxDelay.D(),
+			MaxRetries:        cfg.MaxRetries,
+			ReadTimeout:       cfg.ReadTimeout.D(),
+		}))
+	})
+	Register("images", func(cfg config.CameraConfig) (Source, error) {
+		if cfg.Images == "" {
+			return nil, fmt.Errorf("camera type \"images\" needs an images glob or directory")
+		}
+		return source(NewImages(cfg.Images, cfg.FPS, cfg.Loop))
+	})
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Elliot727/gocvkit/internal/suggest"
+)
+
+// codecs maps each known FOURCC to the containers it can be written to.
+var codecs = map[string][]string{
+	"mp4v": {"mp4", "mov", "avi", "mkv"}, // MPEG-4 Part 2, the default
+	"avc1": {"mp4", "mov", "mkv"},        // H.264 (needs an OpenCV build with an H.264 encoder)
+	"H264": {"avi", "mkv", "mp4"},        // H.264, AVI-style tag
+	"hvc1": {"mp4", "mov", "mkv"},        // H.265
+	"hev1": {"mp4", "mov", "mkv"},        // H.265
+	"MJPG": {"avi", "mkv", "mov"},        // Motion JPEG: large files, cheap to encode, honours quality
+	"XVID": {"avi", "mkv"},               // MPEG-4 Part 2 (Xvid)
+	"DIVX": {"avi", "mkv"},               // MPEG-4 Part 2 (DivX)
+	"FFV1": {"avi", "mkv"},               // FFV1: lossless
+	"VP80": {"webm", "mkv"},              // VP8
+	"VP90": {"webm", "mkv"},              // VP9
+	"av01": {"mp4", "mkv", "webm"},       // AV1
+}
+
+// containers lists the known container formats (file extensions).
+var containers = []string{"avi", "mkv", "mov", "mp4", "webm"}
+
+// ContainerFor returns the container a recording named output is written
+// in: Container, else the extension of output, else "mp4".
+func (r RecordSettings) ContainerFor(output string) string {
+	if r.Container != "" {
+		return r.Container
+	}
+	if ext := strings.TrimPrefix(filepath.Ext(output), "."); ext != "" {
+		return strings.ToLower(ext)
+	}
+	return "mp4"
+}
+
+// checkCodec checks that codec is a known FOURCC that can be written to container.
+func checkCodec(codec, container string) error {
+	if len(codec) != 4 {
+		return fmt.Errorf("codec %q is not a FOURCC (four characters, e.g. \"mp4v\" or \"MJPG\")", codec)
+	}
+	if !slices.Contains(containers, container) {
+		msg := fmt.Sprintf("unknown container %q", container)
+		if s := suggest.Closest(container, containers); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+		return fmt.Errorf("%s; use one of %s", msg, strings.Join(containers, ", "))
+	}
+
+	fits, ok := codecs[codec]
+	if !ok {
+		known := make([]string, 0, len(codecs))
+		for c := range codecs {
+			if strings.EqualFold(c, codec) {
+				return fmt.Errorf("unknown codec %q (did you mean %q? FOURCCs are case-sensitive)", codec, c)
+			}
+			known = append(known, c)
+		}
+		sort.Strings(known)
+		return fmt.Errorf("unknown codec %q; use one of %s", codec, strings.Join(known, ", "))
+	}
+	if !slices.Contains(fits, container) {
+		return fmt.Errorf("codec %q cannot be written to a %s file; use %s", codec, container, strings.Join(fits, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCodec(t *testing.T) {
+	for _, ok := range [][2]string{{"mp4v", "mp4"}, {"MJPG", "avi"}, {"FFV1", "mkv"}, {"VP90", "webm"}} {
+		if err := checkCodec(ok[0], ok[1]); err != nil {
+			t.Errorf("checkCodec(%q, %q) = %v", ok[0], ok[1], err)
+		}
+	}
+
+	// Each mistake gets a hint that names the fix.
+	bad := []struct{ codec, container, hint string }{
+		{"MP4V", "mp4", `did you mean "mp4v"? FOURCCs are case-sensitive`},
+		{"FFV1", "mp4", "use avi, mkv"},
+		{"mp4v", "webn", `did you mean "webm"?`},
+		{"h264x", "mp4", "not a FOURCC"},
+		{"ABCD", "mp4", "use one of DIVX, FFV1"},
+	}
+	for _, b := range bad {
+		err := checkCodec(b.codec, b.container)
+		if err == nil || !strings.Contains(err.Error(), b.hint) {
+			t.Errorf("checkCodec(%q, %q) = %v, want a hint %q", b.codec, b.container, err, b.hint)
+		}
+	}
+}
+
+func TestContainerFor(t *testing.T) {
+	if got := (RecordSettings{}).ContainerFor("rec/out.MKV"); got != "mkv" {
+		t.Errorf("from the extension: %q, want mkv", got)
+	}
+	if got := (RecordSettings{Container: "avi"}).ContainerFor("out.mp4"); got != "avi" {
+		t.Errorf("container set: %q, want avi", got)
+	}
+	if got := (RecordSettings{}).ContainerFor("rec/%H"); got != "mp4" {
+		t.Errorf("no extension: %q, want mp4", got)
+	}
+	if err := (RecordSettings{Quality: 200}).validate(); err == nil {
+		t.Error("quality 200 accepted")
+	}
+}
@@ -6,8 +6,18 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Elliot727/gocvkit/internal/suggest"
 
 	"github.com/BurntSushi/toml"
 )
@@ -15,45 +25,262 @@ import (
 // Config represents the complete application configuration.
 type Config struct {
 	App struct {
-		WindowName string `toml:"window_name"` // WindowName is the title for the display window
-		Record     bool   `toml:"record"`      // Record enables video recording when set to true
-		Output     string `toml:"output"`      // Output is the path for the recorded video file
-	} `toml:"app"`
+		WindowName string `toml:"window_name" json:"window_name"` // WindowName is the title for the display window
+		Record     bool   `toml:"record" json:"record"`           // Record enables video recording when set to true
+		Output     string `toml:"output" json:"output"`           // Output is the path for the recorded video file (unless [[record.outputs]] are given); strftime directives (%Y, %H, ...) make it a per-segment template
+		Headless   bool   `toml:"headless" json:"headless"`       // Headless skips the display window entirely (servers, CI)
+		RecordMeta bool   `toml:"record_meta" json:"record_meta"` // RecordMeta writes per-frame metadata to a JSON Lines file next to each recording
+		Permissive bool   `toml:"permissive" json:"permissive"`   // Permissive logs unknown config keys and step parameters instead of rejecting them
+	} `toml:"app" json:"app"`
+
+	Camera CameraConfig `toml:"camera" json:"camera"`
+
+	// Cameras, when set, replaces [camera] with several cameras, each with its
+	// own pipeline and outputs ([[cameras]] array of tables).
+	Cameras []CameraEntry `toml:"cameras" json:"cameras"`
+
+	Sync struct {
+		Enabled   bool         `toml:"enabled" json:"enabled"`     // Enabled groups the frames of all [[cameras]] by nearest timestamp and runs Steps on each group
+		Tolerance Duration     `toml:"tolerance" json:"tolerance"` // Tolerance is the largest timestamp difference within a group (default 50ms)
+		Steps     []StepConfig `toml:"steps" json:"steps"`         // Steps is the pipeline for a group; the first must combine the views, e.g. "Tile" (default)
+	} `toml:"sync" json:"sync"`
+
+	Record RecordConfig `toml:"record" json:"record"`
 
-	Camera struct {
-		DeviceID int    `toml:"device_id"` // DeviceID is the camera device index (ignored if File is set)
-		File     string `toml:"file"`      // File is the path to a video file (takes precedence over DeviceID)
-	} `toml:"camera"`
+	Clips struct {
+		Enabled bool      `toml:"enabled" json:"enabled"` // Enabled records a clip around every event ([app] record is independent of it)
+		Output  string    `toml:"output" json:"output"`   // Output names the clips, usually a strftime template (default "clips/clip-%Y%m%d-%H%M%S.mp4")
+		Pre     *Duration `toml:"pre" json:"pre"`         // Pre is how much video before the event each clip starts with (default 5s; "0s" = none; held in memory)
+		Post    *Duration `toml:"post" json:"post"`       // Post is how long a clip runs on after the last event (default 10s; "0s" = stop with the event)
+		Events  []string  `toml:"events" json:"events"`   // Events lists the frame events that start a clip (default: any)
+	} `toml:"clips" json:"clips"`
+
+	Snapshots struct {
+		Enabled  bool     `toml:"enabled" json:"enabled"`     // Enabled saves output frames as images: every Interval, on the 's' key and through the API
+		Output   string   `toml:"output" json:"output"`       // Output names the images; strftime directives and %N (sequence number) expand, the extension picks PNG, JPEG or WebP (default "snapshots/snap-%Y%m%d-%H%M%S-%N.png")
+		Interval Duration `toml:"interval" json:"interval"`   // Interval saves a frame every interval, aligned to the clock (0 = on demand only)
+		Quality  int      `toml:"quality" json:"quality"`     // Quality is the JPEG/WebP quality, 1-100 (0 = encoder default)
+		MaxFiles int      `toml:"max_files" json:"max_files"` // MaxFiles deletes the oldest snapshots beyond this many (0 = keep all)
+	} `toml:"snapshots" json:"snapshots"`
 
 	Stream struct {
-		Enabled bool   `toml:"enabled"`
-		Port    int    `toml:"port"`
-		Path    string `toml:"path"`
-		Quality int    `toml:"quality"`
-	}
+		Enabled bool   `toml:"enabled" json:"enabled"`
+		Port    int    `toml:"port" json:"port"` // Port of the HTTP server, shared with [metrics]
+		Path    string `toml:"path" json:"path"`
+		Quality int    `toml:"quality" json:"quality"`
+	} `toml:"stream" json:"stream"`
+
+	Metrics struct {
+		Enabled bool   `toml:"enabled" json:"enabled"` // Enabled serves Prometheus metrics on the [stream] port
+		Path    string `toml:"path" json:"path"`       // Path of the metrics endpoint (default "/metrics")
+	} `toml:"metrics" json:"metrics"`
+
+	API struct {
+		Enabled bool   `toml:"enabled" json:"enabled"` // Enabled serves the REST control API on the [stream] port
+		Path    string `toml:"path" json:"path"`       // Path is the URL prefix of the API (default "/api")
+		Remote  bool   `toml:"remote" json:"remote"`   // Remote answers API requests from other machines too (default: only from this one); needs Token
+		Token   string `toml:"token" json:"-"`         // Token, when set, must be sent as "Authorization: Bearer <token>" (never served back by GET /api/config)
+	} `toml:"api" json:"api"`
+
+	Pipeline struct {
+		Steps   []StepConfig `toml:"steps" json:"steps"`     // Steps contains the ordered list of processing steps
+		Workers int          `toml:"workers" json:"workers"` // Workers runs that many pipeline replicas in parallel (0 or 1 = sequential)
+
+		// Backpressure is what a full frame queue (camera → pipeline → outputs) does:
+		// "block" (default) waits, "drop-oldest" discards the oldest queued frame,
+		// "latest-only" keeps just the newest frame.
+		//
+		// The default is "block" for live sources too: losing frames must be
+		// asked for, since recordings, clips and frame indexes would have gaps,
+		// and a source set in code (WithSource) cannot be told apart from a
+		// webcam here.
+		Backpressure string `toml:"backpressure" json:"backpressure"`
+		QueueDepth   int    `toml:"queue_depth" json:"queue_depth"` // QueueDepth is the number of frames each queue holds (default 10; 1, the only depth allowed, with "latest-only")
+	} `toml:"pipeline" json:"pipeline"`
+}
+
+// Backpressure policies for [pipeline] backpressure.
+const (
+	BackpressureBlock      = "block"       // BackpressureBlock waits for room: no frame is lost, latency grows (files, batch jobs)
+	BackpressureDropOldest = "drop-oldest" // BackpressureDropOldest discards the oldest queued frame to make room (live sources)
+	BackpressureLatestOnly = "latest-only" // BackpressureLatestOnly queues only the newest frame: lowest latency
+)
+
+// CameraConfig is the [camera] table: which source frames come from and how
+// it is opened.
+type CameraConfig struct {
+	Type    string                 `toml:"type" json:"type"`       // Type picks a registered source ("device", "file", "url", "images", "pattern" or a custom one); empty = inferred from the fields below
+	Options map[string]interface{} `toml:"options" json:"options"` // Options are free-form settings for custom source types ([camera.options])
+
+	DeviceID int    `toml:"device_id" json:"device_id"` // DeviceID is the camera device index (ignored if File is set)
+	File     string `toml:"file" json:"file"`           // File is the path to a video file (takes precedence over DeviceID)
+	URL      string `toml:"url" json:"url"`             // URL is a network stream such as rtsp:// or http:// (takes precedence over File)
+	Images   string `toml:"images" json:"images"`       // Images is a glob or directory of image files read as frames, in sorted order (takes precedence over File)
+
+	// Frame size and rate: requested from a webcam, or generated by a test pattern.
+	// FPS also paces an image sequence (0 = as fast as the pipeline runs).
+	Width  int     `toml:"width" json:"width"`   // Width of the frames (pattern default 640)
+	Height int     `toml:"height" json:"height"` // Height of the frames (pattern default 480)
+	FPS    float64 `toml:"fps" json:"fps"`       // FPS is the frame rate
+
+	// Webcams only (device_id); unset keys keep the driver default:
+	FourCC       string   `toml:"fourcc" json:"fourcc"`               // FourCC is the pixel format, e.g. "MJPG" for high resolutions over USB
+	AutoExposure *bool    `toml:"auto_exposure" json:"auto_exposure"` // AutoExposure switches automatic exposure on or off
+	Exposure     *float64 `toml:"exposure" json:"exposure"`           // Exposure is the manual exposure in driver units (set auto_exposure = false)
+	Gain         *float64 `toml:"gain" json:"gain"`                   // Gain is the sensor gain in driver units
+	Focus        *float64 `toml:"focus" json:"focus"`                 // Focus is the manual focus in driver units (disables autofocus)
+	BufferSize   int      `toml:"buffer_size" json:"buffer_size"`     // BufferSize is the driver frame queue length (1 = lowest latency)
+
+	// Video files and image sequences:
+	Loop       bool     `toml:"loop" json:"loop"`               // Loop restarts from the start after the last frame
+	StartTime  Duration `toml:"start_time" json:"start_time"`   // StartTime skips a video file to this position
+	EndTime    Duration `toml:"end_time" json:"end_time"`       // EndTime stops a video file at this position (0 = end of file)
+	StartFrame int      `toml:"start_frame" json:"start_frame"` // StartFrame skips a video file to this frame index (overrides StartTime)
+	EndFrame   int      `toml:"end_frame" json:"end_frame"`     // EndFrame stops a video file before this frame index (overrides EndTime)
+	Speed      Speed    `toml:"speed" json:"speed"`             // Speed is the video file playback rate, 0.25–8 or "max" (default 1)
+
+	// Test patterns only (type = "pattern"; Width, Height and FPS above also apply):
+	Pattern string `toml:"pattern" json:"pattern"` // Pattern is "bars", "box", "checkerboard" or "noise" (default "bars")
+	Frames  int    `toml:"frames" json:"frames"`   // Frames ends the source after that many frames (0 = endless)
+	Seed    int64  `toml:"seed" json:"seed"`       // Seed makes the noise pattern reproducible
+	Counter bool   `toml:"counter" json:"counter"` // Counter burns the frame number into each frame
+
+	// Network streams only (URL):
+	ReconnectDelay    Duration `toml:"reconnect_delay" json:"reconnect_delay"`         // ReconnectDelay is the wait before the first reconnect attempt (default 1s)
+	ReconnectMaxDelay Duration `toml:"reconnect_max_delay" json:"reconnect_max_delay"` // ReconnectMaxDelay caps the doubling backoff between attempts (default 30s)
+	MaxRetries        int      `toml:"max_retries" json:"max_retries"`                 // MaxRetries is the number of reconnect attempts before giving up (0 = forever)
+	ReadTimeout       Duration `toml:"read_timeout" json:"read_timeout"`               // ReadTimeout bounds opening the stream and waiting for a frame (default 10s)
+}
+
+// CameraEntry is one element of [[cameras]]: a source configured exactly
+// like [camera], plus the per-camera outputs. Unset outputs are derived from
+// the [app] and [stream] settings and the camera name.
+type CameraEntry struct {
+	Name       string `toml:"name" json:"name"`               // Name identifies the camera in windows, file names, stream paths and frame tags (default "cam0", "cam1", ...)
+	WindowName string `toml:"window_name" json:"window_name"` // WindowName is the title of the camera's window (default "<window_name> - <name>")
+	Output     string `toml:"output" json:"output"`           // Output is the camera's recording (default "<name>_<output>")
+	StreamPath string `toml:"stream_path" json:"stream_path"` // StreamPath serves the camera's frames on the [stream] port (default "<path>/<name>")
+
+	CameraConfig // CameraConfig holds the source keys, as in [camera]
 
 	Pipeline struct {
-		Steps []StepConfig `toml:"steps"` // Steps contains the ordered list of processing steps
-	} `toml:"pipeline"`
+		Steps []StepConfig `toml:"steps" json:"steps"` // Steps is the camera's own pipeline (default: [pipeline] steps)
+	} `toml:"pipeline" json:"pipeline"`
 }
 
 // StepConfig holds the name and a map of ALL other parameters.
-// We removed the struct tags because we are using UnmarshalTOML below.
+// We removed the struct tags because we are using UnmarshalTOML (and the
+// JSON equivalents) below.
+//
+// Three keys are reserved for routing between named buffers and never reach Params:
+//
+//	input = "source"  // read from a named buffer instead of the previous step's output
+//	tap   = "edges"   // also store this step's output under a name
+//	with  = "edges"   // second operand of a merge step (Add, Blend, ApplyMask, ...)
+//
+// The name "source" always refers to the unmodified input frame.
 type StepConfig struct {
 	Name   string                 // Name of the processor step
+	Input  string                 // Input names the buffer this step reads ("" = previous step's output)
+	Tap    string                 // Tap stores this step's output under the given name
+	With   string                 // With names the second buffer consumed by merge steps
 	Params map[string]interface{} // Params contains all additional configuration parameters
+
+	// Permissive makes unknown Params a warning instead of an error.
+	// It is never read from the step table; the builder copies it from [app] permissive.
+	Permissive bool
 }
 
 // UnmarshalTOML is a hook called automatically by the TOML parser.
 // It gives us the raw map, allowing us to manually extract 'name'
-// and keep everything else as params.
+// (and the routing keys) and keep everything else as params.
 func (s *StepConfig) UnmarshalTOML(data interface{}) error {
 	// 1. Cast the raw data to a map
 	raw, ok := data.(map[string]interface{})
 	if !ok {
 		return fmt.Errorf("expected TOML table for step, got %T", data)
 	}
+	return s.fromMap(raw)
+}
+
+// UnmarshalJSON accepts the same flat shape as a TOML step table:
+// {"name": "Canny", "low": 40, "tap": "edges"}.
+func (s *StepConfig) UnmarshalJSON(data []byte) error {
+	raw, err := DecodeJSONTable(data)
+	if err != nil {
+		return err
+	}
+	return s.fromMap(raw)
+}
 
+// MarshalJSON writes the step in the same flat shape UnmarshalJSON accepts.
+func (s StepConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Table())
+}
+
+// Table returns the step as a flat table, as it would appear in TOML:
+// its parameters plus "name" and any routing keys.
+func (s StepConfig) Table() map[string]interface{} {
+	out := make(map[string]interface{}, len(s.Params)+4)
+	for k, v := range s.Params {
+		out[k] = v
+	}
+	out["name"] = s.Name
+	for key, v := range map[string]string{"input": s.Input, "tap": s.Tap, "with": s.With} {
+		if v != "" {
+			out[key] = v
+		}
+	}
+	return out
+}
+
+// StepFromTable builds a StepConfig from a flat table, the inverse of Table.
+func StepFromTable(raw map[string]interface{}) (StepConfig, error) {
+	var s StepConfig
+	err := s.fromMap(raw)
+	return s, err
+}
+
+// DecodeJSONTable decodes a JSON object into a table with TOML value types:
+// whole numbers become int64 and other numbers float64, in nested arrays and
+// objects too, so the values decode into processor fields exactly as they
+// would from a config file.
+func DecodeJSONTable(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var raw map[string]interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return tomlValue(raw).(map[string]interface{}), nil
+}
+
+// tomlValue replaces the json.Numbers in v, however deeply nested, with
+// int64 or float64.
+func tomlValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = tomlValue(e)
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = tomlValue(e)
+		}
+	}
+	return v
+}
+
+// fromMap fills the step from a raw table, consuming raw.
+func (s *StepConfig) fromMap(raw map[string]interface{}) error {
 	// 2. Extract the Name field manually
 	if name, ok := raw["name"].(string); ok {
 		s.Name = name
@@ -63,11 +290,138 @@ func (s *StepConfig) UnmarshalTOML(data interface{}) error {
 		return fmt.Errorf("pipeline step missing 'name' field")
 	}
 
-	// 3. Assign the remaining fields to Params
+	// 3. Extract the optional routing keys the same way
+	for key, dst := range map[string]*string{"input": &s.Input, "tap": &s.Tap, "with": &s.With} {
+		v, ok := raw[key]
+		if !ok {
+			continue
+		}
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("step %q: %q must be a string, got %T", s.Name, key, v)
+		}
+		*dst = str
+		delete(raw, key)
+	}
+
+	// 4. Assign the remaining fields to Params
 	s.Params = raw
 	return nil
 }
 
+// Clone returns a deep copy of the step, so its Params can be changed
+// without affecting the original.
+func (s StepConfig) Clone() StepConfig {
+	c := s
+	if s.Params != nil {
+		c.Params = make(map[string]interface{}, len(s.Params))
+		for k, v := range s.Params {
+			c.Params[k] = v
+		}
+	}
+	return c
+}
+
+// Clone returns a deep copy of the configuration, so a modified copy can be
+// validated and applied while the original stays in use.
+func (c *Config) Clone() *Config {
+	cp := *c
+	cp.Camera = c.Camera.Clone()
+	cp.Pipeline.Steps = cloneSteps(c.Pipeline.Steps)
+	cp.Sync.Steps = cloneSteps(c.Sync.Steps)
+	cp.Clips.Events = append([]string(nil), c.Clips.Events...)
+	cp.Record.Outputs = append([]RecordOutput(nil), c.Record.Outputs...)
+	if c.Cameras != nil {
+		cp.Cameras = make([]CameraEntry, len(c.Cameras))
+		for i, e := range c.Cameras {
+			e.CameraConfig = e.CameraConfig.Clone()
+			e.Pipeline.Steps = cloneSteps(e.Pipeline.Steps)
+			cp.Cameras[i] = e
+		}
+	}
+	return &cp
+}
+
+// Clone returns a deep copy of the camera settings.
+func (c CameraConfig) Clone() CameraConfig {
+	cp := c
+	if c.Options != nil {
+		cp.Options = make(map[string]interface{}, len(c.Options))
+		for k, v := range c.Options {
+			cp.Options[k] = v
+		}
+	}
+	return cp
+}
+
+func cloneSteps(steps []StepConfig) []StepConfig {
+	if steps == nil {
+		return nil
+	}
+	out := make([]StepConfig, len(steps))
+	for i, sc := range steps {
+		out[i] = sc.Clone()
+	}
+	return out
+}
+
+// ForCamera returns the configuration of the i-th [[cameras]] entry as a
+// single-camera Config: its source becomes [camera], its steps (or the shared
+// [pipeline] steps) the pipeline, and its window, output and stream path are
+// filled in, as are the paths of its [[record.outputs]]. The [[cameras]] and
+// [sync] tables are left out.
+func (c *Config) ForCamera(i int) *Config {
+	cp := c.Clone()
+	e := cp.Cameras[i]
+	cp.Cameras = nil
+	cp.Sync.Enabled = false
+	cp.Sync.Steps = nil
+
+	cp.Camera = e.CameraConfig
+	if e.Pipeline.Steps != nil {
+		cp.Pipeline.Steps = e.Pipeline.Steps
+	}
+
+	cp.App.WindowName = e.WindowName
+	if cp.App.WindowName == "" {
+		cp.App.WindowName = c.App.WindowName + " - " + e.Name
+	}
+	cp.App.Output = e.Output
+	if cp.App.Output == "" {
+		out := c.App.Output
+		if out == "" {
+			out = DefaultOutput
+		}
+		cp.App.Output = prefixFile(out, e.Name)
+	}
+	// The camera records its input, its output and its own taps; taps of
+	// the [sync] pipeline are recorded by the multi-camera App.
+	taps := cp.Taps()
+	outs := cp.Record.Outputs[:0]
+	for _, o := range cp.Record.Outputs {
+		if o.Source != "" && o.Source != SourceOutput && o.Source != SourceInput && !slices.Contains(taps, o.Source) {
+			continue
+		}
+		o.Path = prefixFile(o.Path, e.Name)
+		outs = append(outs, o)
+	}
+	if len(c.Record.Outputs) > 0 && len(outs) == 0 {
+		cp.App.Record = false // nothing left for this camera to record
+	}
+	cp.Record.Outputs = outs
+	if c.Clips.Output != "" {
+		cp.Clips.Output = prefixFile(c.Clips.Output, e.Name)
+	}
+	if c.Snapshots.Output != "" {
+		cp.Snapshots.Output = prefixFile(c.Snapshots.Output, e.Name)
+	}
+	cp.Stream.Path = e.StreamPath
+	if cp.Stream.Path == "" {
+		cp.Stream.Path = strings.TrimSuffix(c.Stream.Path, "/") + "/" + e.Name
+	}
+	return cp
+}
+
 // Load reads and parses the TOML configuration file at the given path.
 // Returns a Config struct with default values applied if not present in the file.
 func Load(path string) (*Config, error) {
@@ -77,13 +431,242 @@ func Load(path string) (*Config, error) {
 	}
 
 	var cfg Config
-	if err := toml.Unmarshal(data, &cfg); err != nil {
+	md, err := toml.Decode(string(data), &cfg)
+	if err != nil {
 		return nil, err
 	}
 
-	if cfg.App.WindowName == "" {
-		cfg.App.WindowName = "GoCV Live"
+	// Reject keys that match no setting, unless the config opts out.
+	if err := checkUndecoded(md.Undecoded()); err != nil {
+		if !cfg.App.Permissive {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		log.Printf("⚠️  %s: %v (ignored: permissive mode)", path, err)
+	}
+
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 
 	return &cfg, nil
 }
+
+// Validate checks settings whose values are restricted, after SetDefaults.
+// Load calls it automatically.
+func (c *Config) Validate() error {
+	switch c.Pipeline.Backpressure {
+	case BackpressureBlock, BackpressureDropOldest, BackpressureLatestOnly:
+	default:
+		msg := fmt.Sprintf("unknown pipeline.backpressure %q", c.Pipeline.Backpressure)
+		if s := suggest.Closest(c.Pipeline.Backpressure, []string{BackpressureBlock, BackpressureDropOldest, BackpressureLatestOnly}); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+		return fmt.Errorf("%s; use %q, %q or %q", msg, BackpressureBlock, BackpressureDropOldest, BackpressureLatestOnly)
+	}
+	if c.API.Enabled && c.API.Remote && c.API.Token == "" {
+		return fmt.Errorf("api.remote exposes the control API to the network; set api.token too")
+	}
+	if c.Pipeline.QueueDepth < 0 {
+		return fmt.Errorf("pipeline.queue_depth must be >= 0, got %d", c.Pipeline.QueueDepth)
+	}
+	if c.Pipeline.Backpressure == BackpressureLatestOnly && c.Pipeline.QueueDepth > 1 {
+		return fmt.Errorf("pipeline.queue_depth = %d contradicts backpressure %q, which holds one frame; remove queue_depth or use %q", c.Pipeline.QueueDepth, BackpressureLatestOnly, BackpressureDropOldest)
+	}
+	if (c.Clips.Pre != nil && *c.Clips.Pre < 0) || (c.Clips.Post != nil && *c.Clips.Post < 0) {
+		return fmt.Errorf("clips.pre and clips.post must not be negative")
+	}
+	if sc := c.Snapshots; sc.Enabled {
+		switch ext := strings.ToLower(filepath.Ext(sc.Output)); ext {
+		case ".png", ".jpg", ".jpeg", ".webp":
+		default:
+			return fmt.Errorf("snapshots.output %q must end in .png, .jpg, .jpeg or .webp", sc.Output)
+		}
+	}
+	if c.Snapshots.Interval < 0 || c.Snapshots.MaxFiles < 0 {
+		return fmt.Errorf("snapshots.interval and snapshots.max_files must not be negative")
+	}
+	if c.Snapshots.Quality < 0 || c.Snapshots.Quality > 100 {
+		return fmt.Errorf("snapshots.quality must be between 0 and 100, got %d", c.Snapshots.Quality)
+	}
+	return c.validateRecord()
+}
+
+// checkUndecoded turns keys the decoder could not place into an error with a
+// "did you mean" hint drawn from the Config struct's toml tags.
+func checkUndecoded(keys []toml.Key) error {
+	var msgs []string
+	reported := make(map[string]bool)
+	for _, key := range keys {
+		// Step tables are decoded by StepConfig; the processor checks their params.
+		if inSteps(key) {
+			continue
+		}
+		// Report an unknown table once, not every key inside it.
+		if len(key) > 1 && reported[key[:len(key)-1].String()] {
+			reported[key.String()] = true
+			continue
+		}
+		reported[key.String()] = true
+
+		msg := fmt.Sprintf("unknown config key %q", key.String())
+		if s := suggest.Closest(key[len(key)-1], tableKeys(key[:len(key)-1])); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
+
+// prefixFile puts "<name>_" in front of the file name of path.
+func prefixFile(path, name string) string {
+	dir, file := filepath.Split(path)
+	return filepath.Join(dir, name+"_"+file)
+}
+
+// stepType is the table type whose keys are processor parameters.
+var stepType = reflect.TypeOf(StepConfig{})
+
+// inSteps reports whether key lies inside a pipeline step table, such as
+// pipeline.steps, cameras.pipeline.steps or sync.steps.
+func inSteps(key toml.Key) bool {
+	for i := 1; i < len(key); i++ {
+		if t, ok := tableType(key[:i]); ok && t == stepType {
+			return true
+		}
+	}
+	return false
+}
+
+// tableType returns the struct type of the Config table at path, looking
+// through arrays of tables such as [[cameras]].
+func tableType(path toml.Key) (reflect.Type, bool) {
+	t := reflect.TypeOf(Config{})
+	for _, name := range path {
+		f, ok := fieldByKey(t, name)
+		if !ok {
+			return nil, false
+		}
+		t = f.Type
+		if t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, false
+		}
+	}
+	return t, true
+}
+
+// tableKeys lists the toml keys of the Config table at path (nil = top level).
+func tableKeys(path toml.Key) []string {
+	t, ok := tableType(path)
+	if !ok {
+		return nil
+	}
+	return structKeys(t)
+}
+
+// structKeys lists the toml keys of t, including those of embedded structs.
+func structKeys(t reflect.Type) []string {
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+		if f.Anonymous && key == "" && f.Type.Kind() == reflect.Struct {
+			keys = append(keys, structKeys(f.Type)...)
+			continue
+		}
+		if key != "" && key != "-" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			if inner, ok := fieldByKey(f.Type, key); ok {
+				return inner, true
+			}
+			continue
+		}
+		if tag == key {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// SetDefaults fills in default values for any settings left empty.
+// Load calls it automatically; call it yourself on a Config built in code.
+func (c *Config) SetDefaults() {
+	if c.App.WindowName == "" {
+		c.App.WindowName = "GoCV Live"
+	}
+	if c.Camera.ReconnectDelay == 0 {
+		c.Camera.ReconnectDelay = Duration(time.Second)
+	}
+	if c.Camera.ReconnectMaxDelay == 0 {
+		c.Camera.ReconnectMaxDelay = Duration(30 * time.Second)
+	}
+	if c.Camera.ReadTimeout == 0 {
+		c.Camera.ReadTimeout = Duration(10 * time.Second)
+	}
+	for i := range c.Cameras {
+		if c.Cameras[i].Name == "" {
+			c.Cameras[i].Name = fmt.Sprintf("cam%d", i)
+		}
+	}
+	if c.Sync.Tolerance == 0 {
+		c.Sync.Tolerance = Duration(50 * time.Millisecond)
+	}
+	if c.Pipeline.Backpressure == "" {
+		c.Pipeline.Backpressure = BackpressureBlock
+	}
+	if c.Pipeline.QueueDepth == 0 {
+		c.Pipeline.QueueDepth = 10
+		if c.Pipeline.Backpressure == BackpressureLatestOnly {
+			c.Pipeline.QueueDepth = 1
+		}
+	}
+	if c.Record.Codec == "" {
+		c.Record.Codec = "mp4v"
+	}
+	c.Record.Container = strings.ToLower(strings.TrimPrefix(c.Record.Container, "."))
+	if c.Clips.Output == "" {
+		c.Clips.Output = "clips/clip-%Y%m%d-%H%M%S.mp4"
+	}
+	if c.Clips.Pre == nil {
+		pre := Duration(5 * time.Second)
+		c.Clips.Pre = &pre
+	}
+	if c.Clips.Post == nil {
+		post := Duration(10 * time.Second)
+		c.Clips.Post = &post
+	}
+	if c.Snapshots.Output == "" {
+		c.Snapshots.Output = "snapshots/snap-%Y%m%d-%H%M%S-%N.png"
+	}
+	if c.Stream.Port == 0 {
+		c.Stream.Port = 8080
+	}
+	if c.Stream.Path == "" {
+		c.Stream.Path = "/stream"
+	}
+	if c.Stream.Quality == 0 {
+		c.Stream.Quality = 75
+	}
+	if c.Metrics.Path == "" {
+		c.Metrics.Path = "/metrics"
+	}
+	if c.API.Path == "" {
+		c.API.Path = "/api"
+	}
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestRemoteAPINeedsToken(t *testing.T) {
+	var c Config
+	c.API.Enabled = true
+	c.API.Remote = true
+	c.SetDefaults()
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "api.token") {
+		t.Errorf("Validate() = %v, want an error asking for api.token", err)
+	}
+
+	c.API.Token = "s3cret"
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate() with a token = %v", err)
+	}
+}
+
+func TestDecodeJSONTable(t *testing.T) {
+	got, err := DecodeJSONTable([]byte(`{"ksize": [5, 5], "sigma": 1.5, "roi": {"x": 10, "scale": [0.5, 2]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same table from TOML.
+	var want map[string]interface{}
+	if _, err := toml.Decode("ksize = [5, 5]\nsigma = 1.5\nroi = {x = 10, scale = [0.5, 2]}\n", &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeJSONTable() = %#v\nwant %#v", got, want)
+	}
+}
+
+func TestCheckUndecoded(t *testing.T) {
+	src := `
+[app]
+headles = true
+
+[strem]
+port = 9000
+path = "/s"
+
+[[pipeline.steps]]
+name = "Canny"
+threshold9 = 1   # step parameters are checked by the processor
+
+[[cameras]]
+name = "left"
+devce_id = 1
+`
+	var c Config
+	md, err := toml.Decode(src, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = checkUndecoded(md.Undecoded())
+	if err == nil {
+		t.Fatal("checkUndecoded() = nil")
+	}
+	want := `unknown config key "app.headles" (did you mean "headless"?); ` +
+		`unknown config key "strem" (did you mean "stream"?); ` +
+		`unknown config key "cameras.devce_id" (did you mean "device_id"?)`
+	if err.Error() != want {
+		t.Errorf("checkUndecoded() =\n%v\nwant\n%v", err, want)
+	}
+}
+
+func TestPipelineQueue(t *testing.T) {
+	pipeline := func(backpressure string, depth int) *Config {
+		c := &Config{}
+		c.Pipeline.Backpressure = backpressure
+		c.Pipeline.QueueDepth = depth
+		c.SetDefaults()
+		return c
+	}
+
+	if c := pipeline("", 0); c.Pipeline.Backpressure != BackpressureBlock || c.Pipeline.QueueDepth != 10 {
+		t.Errorf("defaults: %q with depth %d, want block with 10", c.Pipeline.Backpressure, c.Pipeline.QueueDepth)
+	}
+	if c := pipeline(BackpressureLatestOnly, 0); c.Pipeline.QueueDepth != 1 {
+		t.Errorf("latest-only: depth %d, want 1", c.Pipeline.QueueDepth)
+	}
+	if err := pipeline(BackpressureDropOldest, 3).Validate(); err != nil {
+		t.Errorf("drop-oldest with depth 3: %v", err)
+	}
+
+	// A depth above one contradicts latest-only rather than being overridden.
+	if err := pipeline(BackpressureLatestOnly, 5).Validate(); err == nil || !strings.Contains(err.Error(), "contradicts") {
+		t.Errorf("latest-only with depth 5: Validate() = %v", err)
+	}
+	if err := pipeline(BackpressureBlock, -1).Validate(); err == nil {
+		t.Error("negative queue_depth accepted")
+	}
+	if err := pipeline("drop-oldes", 0).Validate(); err == nil || !strings.Contains(err.Error(), `did you mean "drop-oldest"`) {
+		t.Errorf("misspelled backpressure: Validate() = %v", err)
+	}
+}
+
+// An explicit pre or post of zero is kept, unlike a missing one.
+func TestClipWindow(t *testing.T) {
+	window := func(src string) (pre, post time.Duration) {
+		t.Helper()
+		var c Config
+		if _, err := toml.Decode(src, &c); err != nil {
+			t.Fatal(err)
+		}
+		c.SetDefaults()
+		if err := c.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		return c.Clips.Pre.D(), c.Clips.Post.D()
+	}
+
+	if pre, post := window("[clips]\nenabled = true\n"); pre != 5*time.Second || post != 10*time.Second {
+		t.Errorf("defaults: pre %v, post %v; want 5s, 10s", pre, post)
+	}
+	if pre, post := window("[clips]\nenabled = true\npre = \"0s\"\npost = 0\n"); pre != 0 || post != 0 {
+		t.Errorf("zero: pre %v, post %v; want 0, 0", pre, post)
+	}
+
+	neg := Duration(-time.Second)
+	c := Config{}
+	c.SetDefaults()
+	c.Clips.Pre = &neg
+	if err := c.Validate(); err == nil {
+		t.Error("negative clips.pre accepted")
+	}
+}
+
+func TestSnapshotSettings(t *testing.T) {
+	snapshots := func(output string, quality, maxFiles int) error {
+		var c Config
+		c.Snapshots.Enabled = true
+		c.Snapshots.Output = output
+		c.Snapshots.Quality = quality
+		c.Snapshots.MaxFiles = maxFiles
+		c.SetDefaults()
+		return c.Validate()
+	}
+
+	if err := snapshots("", 0, 0); err != nil {
+		t.Errorf("defaults: %v", err)
+	}
+	if err := snapshots("shots/%H%M%S.JPG", 90, 100); err != nil {
+		t.Errorf("JPEG at quality 90: %v", err)
+	}
+	if err := snapshots("snap.gif", 0, 0); err == nil {
+		t.Error("a .gif output was accepted")
+	}
+	if err := snapshots("", 101, 0); err == nil {
+		t.Error("quality 101 was accepted")
+	}
+	if err := snapshots("", 0, -1); err == nil {
+		t.Error("negative max_files was accepted")
+	}
+}
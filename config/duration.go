@@ -0,0 +1,32 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// Duration is a time.Duration written as a string such as "500ms" or "2s" in
+// TOML and JSON. A bare number is read as seconds.
+type Duration time.Duration
+
+// UnmarshalText parses a duration string ("1m30s") or a number of seconds.
+func (d *Duration) UnmarshalText(text []byte) error {
+	if secs, err := strconv.ParseFloat(string(text), 64); err == nil {
+		*d = Duration(secs * float64(time.Second))
+		return nil
+	}
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText writes the duration in time.Duration's string form.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// D returns the value as a time.Duration.
+func (d Duration) D() time.Duration { return time.Duration(d) }
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestDurationTOML(t *testing.T) {
+	var v struct {
+		Timeout Duration `toml:"timeout"`
+		Delay   Duration `toml:"delay"`
+		Tick    Duration `toml:"tick"`
+	}
+	// A string is a Go duration; a bare number, even unquoted, is seconds.
+	if _, err := toml.Decode("timeout = \"1m30s\"\ndelay = 2\ntick = \"0.25\"\n", &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Timeout.D() != 90*time.Second || v.Delay.D() != 2*time.Second || v.Tick.D() != 250*time.Millisecond {
+		t.Errorf("decoded %v, %v, %v; want 1m30s, 2s, 250ms", v.Timeout.D(), v.Delay.D(), v.Tick.D())
+	}
+
+	if _, err := toml.Decode(`timeout = "10 minutes"`, &v); err == nil {
+		t.Error(`"10 minutes" decoded without an error`)
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	b, err := json.Marshal(map[string]Duration{"read_timeout": Duration(1500 * time.Millisecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"read_timeout":"1.5s"}` {
+		t.Errorf("Marshal() = %s", b)
+	}
+
+	var back map[string]Duration
+	if err := json.Unmarshal(b, &back); err != nil || back["read_timeout"].D() != 1500*time.Millisecond {
+		t.Errorf("Unmarshal(%s) = %v, %v", b, back, err)
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Elliot727/gocvkit/internal/suggest"
+)
+
+// DefaultOutput is the recording written when [app] output is not set.
+const DefaultOutput = "gocvkit_capture.mp4"
+
+// Sources a [[record.outputs]] recording can take its frames from; any other
+// source names a pipeline tap.
+const (
+	SourceOutput = "output" // SourceOutput records the processed frames (default)
+	SourceInput  = "input"  // SourceInput records the raw frames as read from the camera, before the pipeline
+)
+
+// RecordSettings are how a recording is encoded, when it starts a new
+// segment file and how long old segments are kept.
+type RecordSettings struct {
+	Codec     string `toml:"codec" json:"codec"`         // Codec is the FOURCC of the video codec, e.g. "mp4v" (default), "avc1", "MJPG", "XVID" or "FFV1" (lossless)
+	Container string `toml:"container" json:"container"` // Container is the file format of recordings and clips, e.g. "mp4" or "mkv", replacing their extension (default: keep it)
+	Quality   int    `toml:"quality" json:"quality"`     // Quality is the encoder quality, 1-100, for codecs whose backend supports it such as MJPG (0 = backend default)
+
+	SegmentTime  Duration `toml:"segment_time" json:"segment_time"`     // SegmentTime starts a new segment every interval, aligned to the clock (0 = never)
+	SegmentSize  Size     `toml:"segment_size" json:"segment_size"`     // SegmentSize starts a new segment once the current one reaches this size (0 = no limit)
+	MaxTotalSize Size     `toml:"max_total_size" json:"max_total_size"` // MaxTotalSize deletes the oldest segments while all of them together are larger (0 = keep all)
+	MaxAge       Duration `toml:"max_age" json:"max_age"`               // MaxAge deletes segments older than this (0 = keep all)
+}
+
+// RecordConfig is the [record] table. [app] record switches recording on;
+// without Outputs the processed frames go to [app] output.
+type RecordConfig struct {
+	RecordSettings // RecordSettings apply to every recording and to [clips]
+
+	// Outputs, when set, replaces [app] output with several recordings made
+	// at once ([[record.outputs]] array of tables).
+	Outputs []RecordOutput `toml:"outputs" json:"outputs"`
+}
+
+// RecordOutput is one element of [[record.outputs]]. Settings left unset
+// are taken from [record].
+type RecordOutput struct {
+	Source string `toml:"source" json:"source"` // Source is "output" (default), "input" or the name of a pipeline tap
+	Path   string `toml:"path" json:"path"`     // Path names the files, like [app] output (required)
+
+	RecordSettings
+}
+
+// Recordings returns the recordings [record] asks for, with every setting
+// resolved: the [[record.outputs]], or else the processed frames written to
+// [app] output. Whether to record at all is up to [app] record.
+func (c *Config) Recordings() []RecordOutput {
+	if len(c.Record.Outputs) == 0 {
+		path := c.App.Output
+		if path == "" {
+			path = DefaultOutput
+		}
+		return []RecordOutput{{Source: SourceOutput, Path: path, RecordSettings: c.Record.RecordSettings}}
+	}
+
+	base := c.Record.RecordSettings
+	outs := make([]RecordOutput, len(c.Record.Outputs))
+	for i, o := range c.Record.Outputs {
+		if o.Source == "" {
+			o.Source = SourceOutput
+		}
+		s := &o.RecordSettings
+		if s.Codec == "" {
+			s.Codec = base.Codec
+		}
+		if s.Container == "" {
+			s.Container = base.Container
+		}
+		s.Container = strings.ToLower(strings.TrimPrefix(s.Container, "."))
+		if s.Quality == 0 {
+			s.Quality = base.Quality
+		}
+		if s.SegmentTime == 0 {
+			s.SegmentTime = base.SegmentTime
+		}
+		if s.SegmentSize == 0 {
+			s.SegmentSize = base.SegmentSize
+		}
+		if s.MaxTotalSize == 0 {
+			s.MaxTotalSize = base.MaxTotalSize
+		}
+		if s.MaxAge == 0 {
+			s.MaxAge = base.MaxAge
+		}
+		outs[i] = o
+	}
+	return outs
+}
+
+// Taps returns the names of the taps written by any step of the
+// configuration's pipelines, sorted.
+func (c *Config) Taps() []string {
+	var taps []string
+	add := func(steps []StepConfig) {
+		for _, s := range steps {
+			if s.Tap != "" && !slices.Contains(taps, s.Tap) {
+				taps = append(taps, s.Tap)
+			}
+		}
+	}
+	add(c.Pipeline.Steps)
+	add(c.Sync.Steps)
+	for _, e := range c.Cameras {
+		add(e.Pipeline.Steps)
+	}
+	slices.Sort(taps)
+	return taps
+}
+
+// validateRecord checks the [record] settings and every recording made
+// with them, including [clips].
+func (c *Config) validateRecord() error {
+	if err := c.Record.RecordSettings.validate(); err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	if c.Clips.Enabled {
+		if err := checkCodec(c.Record.Codec, c.Record.ContainerFor(c.Clips.Output)); err != nil {
+			return fmt.Errorf("clips: %w", err)
+		}
+	}
+	if !c.App.Record {
+		return nil
+	}
+
+	taps := c.Taps()
+	paths := make(map[string]int)
+	for i, o := range c.Recordings() {
+		where := "record"
+		if len(c.Record.Outputs) > 0 {
+			where = fmt.Sprintf("record.outputs[%d]", i)
+		}
+
+		switch {
+		case o.Source == SourceOutput || o.Source == SourceInput || slices.Contains(taps, o.Source):
+		default:
+			names := append([]string{SourceOutput, SourceInput}, taps...)
+			msg := fmt.Sprintf("%s: source %q is neither %q, %q nor a pipeline tap", where, o.Source, SourceOutput, SourceInput)
+			if s := suggest.Closest(o.Source, names); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+			return errors.New(msg)
+		}
+		if o.Path == "" {
+			return fmt.Errorf("%s: path is required", where)
+		}
+		if j, ok := paths[o.Path]; ok {
+			return fmt.Errorf("%s: path %q is already used by record.outputs[%d]", where, o.Path, j)
+		}
+		paths[o.Path] = i
+
+		if err := o.RecordSettings.validate(); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		if err := checkCodec(o.Codec, o.ContainerFor(o.Path)); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the ranges of the settings.
+func (s RecordSettings) validate() error {
+	if s.Quality < 0 || s.Quality > 100 {
+		return fmt.Errorf("quality must be between 0 and 100, got %d", s.Quality)
+	}
+	if s.SegmentTime < 0 || s.MaxAge < 0 || s.SegmentSize < 0 || s.MaxTotalSize < 0 {
+		return fmt.Errorf("segment_time, segment_size, max_total_size and max_age must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// decode reads src the way Load does, without the file.
+func decode(t *testing.T, src string) *Config {
+	t.Helper()
+	var c Config
+	if _, err := toml.Decode(src, &c); err != nil {
+		t.Fatal(err)
+	}
+	c.SetDefaults()
+	return &c
+}
+
+func TestRecordings(t *testing.T) {
+	t.Run("default output", func(t *testing.T) {
+		got := decode(t, "[app]\nrecord = true\n").Recordings()
+		want := []RecordOutput{{Source: SourceOutput, Path: DefaultOutput, RecordSettings: RecordSettings{Codec: "mp4v"}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Recordings() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("app output takes the record settings", func(t *testing.T) {
+		got := decode(t, "[app]\nrecord = true\noutput = \"rec/%H.avi\"\n[record]\ncodec = \"MJPG\"\nquality = 80\nsegment_time = \"10m\"\n").Recordings()
+		want := []RecordOutput{{Source: SourceOutput, Path: "rec/%H.avi", RecordSettings: RecordSettings{
+			Codec: "MJPG", Quality: 80, SegmentTime: Duration(10 * time.Minute),
+		}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Recordings() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("outputs inherit what they leave unset", func(t *testing.T) {
+		got := decode(t, `
+[app]
+record = true
+
+[record]
+codec = "XVID"
+container = ".AVI"
+segment_size = "1GB"
+max_age = "24h"
+
+[[record.outputs]]
+source = "input"
+path = "raw.mkv"
+codec = "FFV1"
+container = "mkv"
+
+[[record.outputs]]
+path = "out.avi"
+max_age = "1h"
+`).Recordings()
+		want := []RecordOutput{
+			{Source: SourceInput, Path: "raw.mkv", RecordSettings: RecordSettings{
+				Codec: "FFV1", Container: "mkv", SegmentSize: 1 << 30, MaxAge: Duration(24 * time.Hour),
+			}},
+			{Source: SourceOutput, Path: "out.avi", RecordSettings: RecordSettings{
+				Codec: "XVID", Container: "avi", SegmentSize: 1 << 30, MaxAge: Duration(time.Hour),
+			}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Recordings() =\n%+v\nwant\n%+v", got, want)
+		}
+	})
+}
+
+func TestRecordOutputsValidate(t *testing.T) {
+	const canny = "[[pipeline.steps]]\nname = \"Canny\"\ntap = \"edges\"\n"
+
+	err := decode(t, "[app]\nrecord = true\n[[record.outputs]]\nsource = \"edgse\"\npath = \"e.mp4\"\n"+canny).Validate()
+	if err == nil || !strings.Contains(err.Error(), `did you mean "edges"`) {
+		t.Errorf("misspelled tap: %v", err)
+	}
+	err = decode(t, "[app]\nrecord = true\n[[record.outputs]]\nsource = \"input\"\n").Validate()
+	if err == nil || !strings.Contains(err.Error(), "path is required") {
+		t.Errorf("output without a path: %v", err)
+	}
+	err = decode(t, "[app]\nrecord = true\n[[record.outputs]]\npath = \"a.mp4\"\n[[record.outputs]]\nsource = \"input\"\npath = \"a.mp4\"\n").Validate()
+	if err == nil || !strings.Contains(err.Error(), "already used by record.outputs[0]") {
+		t.Errorf("two outputs on one path: %v", err)
+	}
+
+	// Outputs are not checked while recording is off.
+	if err := decode(t, "[[record.outputs]]\nsource = \"nope\"\n").Validate(); err != nil {
+		t.Errorf("record off: %v", err)
+	}
+}
+
+// Taps lists each tap once, sorted, from every pipeline including [sync].
+func TestTaps(t *testing.T) {
+	c := decode(t, "[[pipeline.steps]]\nname = \"Grayscale\"\ntap = \"gray\"\n"+
+		"[[pipeline.steps]]\nname = \"Canny\"\ntap = \"edges\"\n"+
+		"[[sync.steps]]\nname = \"Tile\"\ntap = \"edges\"\n"+
+		"[[sync.steps]]\nname = \"Grayscale\"\ntap = \"combined\"\n")
+	if got, want := c.Taps(), []string{"combined", "edges", "gray"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Taps() = %q, want %q", got, want)
+	}
+}
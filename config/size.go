@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Size is a number of bytes written as a string such as "500MB" or "2GB" in
+// TOML and JSON (KB, MB, GB and TB are powers of 1024). A bare number is read
+// as megabytes.
+type Size int64
+
+// sizeUnits are the accepted suffixes, largest first so "MB" is not read as "B".
+var sizeUnits = []struct {
+	suffix string
+	bytes  float64
+}{
+	{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1},
+}
+
+// UnmarshalText parses a size with a unit ("1.5GB") or a number of megabytes.
+func (s *Size) UnmarshalText(text []byte) error {
+	str := strings.ToUpper(strings.TrimSpace(string(text)))
+	if mb, err := strconv.ParseFloat(str, 64); err == nil {
+		*s = Size(mb * (1 << 20))
+		return nil
+	}
+	for _, u := range sizeUnits {
+		if num, ok := strings.CutSuffix(str, u.suffix); ok {
+			v, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+			if err != nil || v < 0 {
+				break
+			}
+			*s = Size(v * u.bytes)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid size %q (use e.g. \"500MB\" or \"2GB\")", string(text))
+}
+
+// MarshalText writes the size with the largest unit that divides it evenly.
+func (s Size) MarshalText() ([]byte, error) {
+	for _, u := range sizeUnits {
+		if s != 0 && int64(s)%int64(u.bytes) == 0 {
+			return []byte(strconv.FormatInt(int64(s)/int64(u.bytes), 10) + u.suffix), nil
+		}
+	}
+	return []byte("0"), nil
+}
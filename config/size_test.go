@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+func TestSizeUnmarshal(t *testing.T) {
+	valid := map[string]Size{
+		"500MB": 500 << 20,
+		"2GB":   2 << 30,
+		"1.5gb": 3 << 29,
+		"1 TB":  1 << 40,
+		"64KB":  64 << 10,
+		"100B":  100,
+		"10":    10 << 20, // a bare number is megabytes
+		"0":     0,
+	}
+	for text, want := range valid {
+		var s Size
+		if err := s.UnmarshalText([]byte(text)); err != nil || s != want {
+			t.Errorf("UnmarshalText(%q) = %d, %v; want %d", text, s, err, want)
+		}
+	}
+
+	for _, text := range []string{"-1GB", "2XB", "MB", ""} {
+		var s Size
+		if err := s.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %d, want an error", text, s)
+		}
+	}
+}
+
+// MarshalText picks the largest whole unit, and its output reads back.
+func TestSizeRoundTrip(t *testing.T) {
+	for size, want := range map[Size]string{0: "0", 2 << 30: "2GB", 1536 << 20: "1536MB", 100: "100B"} {
+		text, _ := size.MarshalText()
+		if string(text) != want {
+			t.Errorf("MarshalText(%d) = %q, want %q", size, text, want)
+		}
+		var back Size
+		if err := back.UnmarshalText(text); err != nil || back != size {
+			t.Errorf("UnmarshalText(%q) = %d, %v; want %d", text, back, err, size)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// SpeedMax plays as fast as the pipeline can process frames.
+var SpeedMax = Speed(math.Inf(1))
+
+// Speed is a playback rate multiplier: 1 is real time, 0.5 half speed.
+// It is written as a number between 0.25 and 8, or as "max" for as fast as
+// possible. The zero value means "not set".
+type Speed float64
+
+// UnmarshalText parses a rate such as "2" or "0.25", or "max".
+func (s *Speed) UnmarshalText(text []byte) error {
+	if string(text) == "max" {
+		*s = SpeedMax
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(text), 64)
+	if err != nil {
+		return fmt.Errorf("invalid speed %q (use a number or \"max\")", text)
+	}
+	if v < 0.25 || v > 8 {
+		return fmt.Errorf("speed must be between 0.25 and 8 (or \"max\"), got %g", v)
+	}
+	*s = Speed(v)
+	return nil
+}
+
+// MarshalText writes the rate as a number, or "max".
+func (s Speed) MarshalText() ([]byte, error) {
+	if math.IsInf(float64(s), 1) {
+		return []byte("max"), nil
+	}
+	return []byte(strconv.FormatFloat(float64(s), 'g', -1, 64)), nil
+}
@@ -0,0 +1,78 @@
+// Package frame defines the metadata that travels alongside each frame's Mat
+// through the pipeline and out to the frame callback, recorder and streamer.
+//
+// Pixels stay in gocv.Mat; everything a step learns about the frame (bounding
+// boxes, contours, keypoints, counts, scores) goes into a Meta:
+//
+//	func (d *Detector) ProcessMeta(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat) error {
+//	    for _, r := range d.find(src) {
+//	        meta.AddDetection(frame.Detection{Label: "face", Box: r})
+//	    }
+//	    meta.SetTag("faces", len(meta.Detections))
+//	    src.CopyTo(dst)
+//	    return nil
+//	}
+//
+// A fresh Meta is created for every frame, so steps never see stale data.
+package frame
+
+import (
+	"image"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// Detection is a single object found in a frame.
+type Detection struct {
+	Label   string          `json:"label,omitempty"`   // Label is the class or name of the object
+	Score   float64         `json:"score,omitempty"`   // Score is the detector's confidence
+	Box     image.Rectangle `json:"box"`               // Box is the bounding box in frame coordinates
+	Contour []image.Point   `json:"contour,omitempty"` // Contour is the optional outline of the object
+}
+
+// Meta carries typed metadata for one frame.
+type Meta struct {
+	Index      int64           `json:"index"`                // Index is the frame's sequence number, counted from 0 at capture; gaps downstream mean dropped frames
+	Timestamp  time.Time       `json:"timestamp"`            // Timestamp is the wall-clock time the frame was captured (with a monotonic reading for latency)
+	Position   time.Duration   `json:"position,omitempty"`   // Position is the frame's place in the source media (file or stream PTS), in nanoseconds in JSON
+	Detections []Detection     `json:"detections,omitempty"` // Detections are objects found by earlier steps
+	Keypoints  []gocv.KeyPoint `json:"keypoints,omitempty"`  // Keypoints are feature points found by earlier steps
+	Tags       map[string]any  `json:"tags,omitempty"`       // Tags holds free-form key/value results (counts, scores, ...)
+	Events     []string        `json:"events,omitempty"`     // Events are signals raised by steps (e.g. "motion"); they trigger event clips
+}
+
+// New returns the metadata for the frame with the given index, stamped now.
+func New(index int64) *Meta {
+	return &Meta{Index: index, Timestamp: time.Now()}
+}
+
+// AddDetection appends a detection to the frame.
+func (m *Meta) AddDetection(d Detection) {
+	m.Detections = append(m.Detections, d)
+}
+
+// AddEvent raises a named event on the frame, e.g. to start an event clip.
+// Raising the same event twice has no further effect.
+func (m *Meta) AddEvent(name string) {
+	for _, e := range m.Events {
+		if e == name {
+			return
+		}
+	}
+	m.Events = append(m.Events, name)
+}
+
+// SetTag stores a key/value pair, replacing any previous value for key.
+func (m *Meta) SetTag(key string, value any) {
+	if m.Tags == nil {
+		m.Tags = make(map[string]any)
+	}
+	m.Tags[key] = value
+}
+
+// Tag returns the value stored under key, if any.
+func (m *Meta) Tag(key string) (any, bool) {
+	v, ok := m.Tags[key]
+	return v, ok
+}
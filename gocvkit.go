@@ -3,6 +3,7 @@
 //
 // Features:
 //   - Modular processing pipeline (Grayscale, Blur, Canny, Sobel)
+//   - Branching pipelines with named taps and merge steps
 //   - TOML configuration for camera, display, and processor settings
 //   - Supports webcam or video file input
 //   - Display window with optional frame callbacks
@@ -26,6 +27,9 @@ package gocvkit
 
 import (
 	"github.com/Elliot727/gocvkit/app"
+	"github.com/Elliot727/gocvkit/camera"
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/frame"
 	"github.com/Elliot727/gocvkit/processor"
 
 	// Import sub-packages to alias them.
@@ -33,11 +37,92 @@ import (
 	"github.com/Elliot727/gocvkit/processor/blurs"
 	"github.com/Elliot727/gocvkit/processor/core"
 	"github.com/Elliot727/gocvkit/processor/edges"
+	"github.com/Elliot727/gocvkit/processor/events"
+	"github.com/Elliot727/gocvkit/processor/merge"
+	"github.com/Elliot727/gocvkit/processor/multi"
 )
 
+// Option customises an App at construction time.
+type Option = app.Option
+
+// Config is the complete application configuration, as loaded from TOML.
+type Config = config.Config
+
+// StepConfig describes a single pipeline step: a processor name and its parameters.
+type StepConfig = config.StepConfig
+
+// Source produces frames for an App: a webcam, file, network stream or a custom producer.
+type Source = camera.Source
+
+// CameraConfig is the [camera] table, handed to source factories.
+type CameraConfig = config.CameraConfig
+
+// Meta is the per-frame metadata passed to MetaProcessable steps and to RunFrames callbacks.
+type Meta = frame.Meta
+
+// Detection is a single object found in a frame, stored in Meta.Detections.
+type Detection = frame.Detection
+
 // NewApp creates a fully configured App instance from a TOML config path.
-func NewApp(cfgPath string) (*app.App, error) {
-	return app.New(cfgPath)
+func NewApp(cfgPath string, opts ...Option) (*app.App, error) {
+	return app.New(cfgPath, opts...)
+}
+
+// NewAppFromConfig creates an App from an in-memory configuration.
+// A nil cfg starts from defaults; no file is watched for changes.
+func NewAppFromConfig(cfg *Config, opts ...Option) (*app.App, error) {
+	return app.NewFromConfig(cfg, opts...)
+}
+
+// WithHeadless runs the App without a display window (servers, CI).
+func WithHeadless() Option {
+	return app.WithHeadless()
+}
+
+// WithConfigWatch hot-reloads the pipeline whenever the TOML file at path changes.
+func WithConfigWatch(path string) Option {
+	return app.WithConfigWatch(path)
+}
+
+// WithDevice captures from the webcam with the given device index.
+func WithDevice(id int) Option {
+	return app.WithDevice(id)
+}
+
+// WithFile reads frames from a video file instead of a webcam.
+func WithFile(path string) Option {
+	return app.WithFile(path)
+}
+
+// WithURL reads frames from a network stream, reconnecting whenever it drops.
+func WithURL(url string) Option {
+	return app.WithURL(url)
+}
+
+// WithSource reads frames from a custom Source instead of the [camera] table.
+func WithSource(src Source) Option {
+	return app.WithSource(src)
+}
+
+// WithSteps replaces the pipeline with the given steps, in order.
+func WithSteps(steps ...StepConfig) Option {
+	return app.WithSteps(steps...)
+}
+
+// WithRecording enables recording of the processed frames to output.
+func WithRecording(output string) Option {
+	return app.WithRecording(output)
+}
+
+// WithStream serves the processed frames as MJPEG on the given port and path.
+func WithStream(port int, path string) Option {
+	return app.WithStream(port, path)
+}
+
+// WithoutSignalHandling leaves SIGINT/SIGTERM handling to the caller, for apps
+// embedded in a larger service that cancels RunContext itself.
+func WithoutSignalHandling() Option {
+	return app.WithoutSignalHandling()
 }
 
 // RegisterProcessor allows external registration of custom processes.
@@ -45,6 +130,11 @@ func RegisterProcessor(name string, item any) {
 	processor.Register(name, item)
 }
 
+// RegisterSource makes a custom frame source available as `[camera] type = "<name>"`.
+func RegisterSource(name string, factory func(CameraConfig) (Source, error)) {
+	camera.Register(name, factory)
+}
+
 // ---------------------------------------------------------
 // EXPORTED FILTERS (Type Aliases)
 // ---------------------------------------------------------
@@ -95,6 +185,9 @@ type Scharr = edges.Scharr
 // BackgroundSubtractor is an alias for edges.BackgroundSubtractor, providing background subtraction.
 type BackgroundSubtractor = edges.BackgroundSubtractor
 
+// Motion is an alias for events.Motion, raising an event when the scene changes.
+type Motion = events.Motion
+
 // GaussianBlur is an alias for blurs.GaussianBlur, providing Gaussian blur filtering.
 type GaussianBlur = blurs.GaussianBlur
 
@@ -103,3 +196,18 @@ type MedianBlur = blurs.MedianBlur
 
 // Bilateral is an alias for blurs.Bilateral, providing bilateral filtering.
 type Bilateral = blurs.Bilateral
+
+// Add is an alias for merge.Add, adding a named buffer to the running frame.
+type Add = merge.Add
+
+// BitwiseAnd is an alias for merge.BitwiseAnd, intersecting the running frame with a named buffer.
+type BitwiseAnd = merge.BitwiseAnd
+
+// ApplyMask is an alias for merge.ApplyMask, keeping only the pixels selected by a mask buffer.
+type ApplyMask = merge.ApplyMask
+
+// Blend is an alias for merge.Blend, mixing the running frame with a named buffer.
+type Blend = merge.Blend
+
+// Tile is an alias for multi.Tile, laying the views of several cameras out in a grid.
+type Tile = multi.Tile
@@ -0,0 +1,110 @@
+// Package strftime expands C strftime-style file name templates such as
+// "rec/%Y-%m-%d/%H%M%S.mp4", and matches the names they produce.
+//
+// Supported directives:
+//
+//	%Y 2006   %y 06    %m 01    %d 02    %j 002 (day of year)
+//	%H 15     %I 03    %M 04    %S 05    %f 000000 (microseconds)
+//	%p PM     %a Mon   %A Monday   %b Jan   %B January
+//	%z -0700  %Z MST   %s Unix seconds   %% a literal %
+//
+// Any other directive is kept as written.
+package strftime
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// directives maps each directive to its formatter and to a regexp for its output.
+var directives = map[byte]struct {
+	format  func(time.Time) string
+	pattern string
+}{
+	'Y': {func(t time.Time) string { return t.Format("2006") }, `\d{4}`},
+	'y': {func(t time.Time) string { return t.Format("06") }, `\d{2}`},
+	'm': {func(t time.Time) string { return t.Format("01") }, `\d{2}`},
+	'd': {func(t time.Time) string { return t.Format("02") }, `\d{2}`},
+	'j': {func(t time.Time) string { return t.Format("002") }, `\d{3}`},
+	'H': {func(t time.Time) string { return t.Format("15") }, `\d{2}`},
+	'I': {func(t time.Time) string { return t.Format("03") }, `\d{2}`},
+	'M': {func(t time.Time) string { return t.Format("04") }, `\d{2}`},
+	'S': {func(t time.Time) string { return t.Format("05") }, `\d{2}`},
+	'f': {func(t time.Time) string { return t.Format(".000000")[1:] }, `\d{6}`},
+	'p': {func(t time.Time) string { return t.Format("PM") }, `[AP]M`},
+	'a': {func(t time.Time) string { return t.Format("Mon") }, `[A-Za-z]{3}`},
+	'A': {func(t time.Time) string { return t.Format("Monday") }, `[A-Za-z]+`},
+	'b': {func(t time.Time) string { return t.Format("Jan") }, `[A-Za-z]{3}`},
+	'B': {func(t time.Time) string { return t.Format("January") }, `[A-Za-z]+`},
+	'z': {func(t time.Time) string { return t.Format("-0700") }, `[-+]\d{4}`},
+	'Z': {func(t time.Time) string { return t.Format("MST") }, `[-+A-Za-z0-9]+`},
+	's': {func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }, `\d+`},
+}
+
+// Format expands the directives in layout for t.
+func Format(layout string, t time.Time) string {
+	var b strings.Builder
+	walk(layout, func(lit string) { b.WriteString(lit) }, func(c byte) {
+		b.WriteString(directives[c].format(t))
+	})
+	return b.String()
+}
+
+// Has reports whether layout contains any directive, i.e. whether its
+// expansion depends on the time.
+func Has(layout string) bool {
+	found := false
+	walk(layout, func(string) {}, func(byte) { found = true })
+	return found
+}
+
+// Glob returns a filepath.Glob pattern matching every expansion of layout.
+func Glob(layout string) string {
+	var b strings.Builder
+	walk(layout, func(lit string) {
+		for _, r := range lit {
+			if strings.ContainsRune(`*?[\`, r) {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		}
+	}, func(byte) { b.WriteString("*") })
+	return b.String()
+}
+
+// Pattern returns a regular expression, not anchored, matching every
+// expansion of layout.
+func Pattern(layout string) string {
+	var b strings.Builder
+	walk(layout, func(lit string) { b.WriteString(regexp.QuoteMeta(lit)) }, func(c byte) {
+		b.WriteString(directives[c].pattern)
+	})
+	return b.String()
+}
+
+// walk splits layout into literal text and known directives.
+func walk(layout string, literal func(string), directive func(byte)) {
+	start := 0
+	for i := 0; i < len(layout)-1; i++ {
+		if layout[i] != '%' {
+			continue
+		}
+		c := layout[i+1]
+		if c == '%' {
+			literal(layout[start:i] + "%")
+			i++
+			start = i + 1
+			continue
+		}
+		if _, ok := directives[c]; !ok {
+			continue
+		}
+		literal(layout[start:i])
+		directive(c)
+		i++
+		start = i + 1
+	}
+	literal(layout[start:])
+}
@@ -0,0 +1,124 @@
+package strftime
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var at = time.Date(2024, time.March, 5, 14, 7, 9, 123456000, time.UTC)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		layout, want string
+	}{
+		{"rec.mp4", "rec.mp4"},
+		{"rec/%Y-%m-%d/%H%M%S.mp4", "rec/2024-03-05/140709.mp4"},
+		{"%y %j %I%p", "24 065 02PM"},
+		{"%a %A %b %B", "Tue Tuesday Mar March"},
+		{"%f", "123456"},
+		{"%s", "1709647629"},
+		{"%z %Z", "+0000 UTC"},
+		{"100%% %Y", "100% 2024"},
+		{"%N-%Q.mp4", "%N-%Q.mp4"}, // unknown directives are kept
+		{"trailing%", "trailing%"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.layout, at); got != tt.want {
+			t.Errorf("Format(%q) = %q, want %q", tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestHas(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   bool
+	}{
+		{"rec.mp4", false},
+		{"rec-%N.mp4", false},
+		{"100%%.mp4", false},
+		{"rec-%H.mp4", true},
+		{"%%%Y", true},
+	}
+	for _, tt := range tests {
+		if got := Has(tt.layout); got != tt.want {
+			t.Errorf("Has(%q) = %v, want %v", tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		layout, want string
+		matches      []string
+		misses       []string
+	}{
+		{
+			layout:  "rec/%Y-%m-%d/cam-%H%M%S.mp4",
+			want:    "rec/*-*-*/cam-***.mp4",
+			matches: []string{"rec/2024-03-05/cam-140709.mp4"},
+			misses:  []string{"rec/2024-03-05/cam-140709.mkv"},
+		},
+		{
+			layout:  "a[1]*?.mp4",
+			want:    `a\[1]\*\?.mp4`,
+			matches: []string{"a[1]*?.mp4"},
+			misses:  []string{"a1x.mp4"},
+		},
+	}
+	for _, tt := range tests {
+		got := Glob(tt.layout)
+		if got != tt.want {
+			t.Errorf("Glob(%q) = %q, want %q", tt.layout, got, tt.want)
+		}
+		for _, name := range tt.matches {
+			if ok, _ := filepath.Match(got, name); !ok {
+				t.Errorf("Glob(%q) does not match %q", tt.layout, name)
+			}
+		}
+		for _, name := range tt.misses {
+			if ok, _ := filepath.Match(got, name); ok {
+				t.Errorf("Glob(%q) matches %q", tt.layout, name)
+			}
+		}
+	}
+}
+
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		layout  string
+		matches []string
+		misses  []string
+	}{
+		{
+			layout:  "rec/%Y-%m-%d/%H%M%S.mp4",
+			matches: []string{"rec/2024-03-05/140709.mp4"},
+			misses:  []string{"rec/2024-3-05/140709.mp4", "rec/2024-03-05/140709.mp4.json", "x/rec/2024-03-05/140709.mp4"},
+		},
+		{
+			layout:  "cam.v1-%p.avi",
+			matches: []string{"cam.v1-AM.avi", "cam.v1-PM.avi"},
+			misses:  []string{"camxv1-AM.avi", "cam.v1-XM.avi"},
+		},
+		{
+			layout:  "frame-%N-%s", // unknown directives are literal
+			matches: []string{"frame-%N-1700000000"},
+			misses:  []string{"frame-7-1700000000"},
+		},
+	}
+	for _, tt := range tests {
+		re := regexp.MustCompile("^" + Pattern(tt.layout) + "$")
+		for _, name := range tt.matches {
+			if !re.MatchString(name) {
+				t.Errorf("Pattern(%q) = %s does not match %q", tt.layout, re, name)
+			}
+		}
+		for _, name := range tt.misses {
+			if re.MatchString(name) {
+				t.Errorf("Pattern(%q) = %s matches %q", tt.layout, re, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package suggest_test
+
+import (
+	"fmt"
+
+	"github.com/Elliot727/gocvkit/internal/suggest"
+)
+
+func ExampleClosest() {
+	steps := []string{"Canny", "GaussianBlur", "Grayscale", "Sobel"}
+	fmt.Printf("%q\n", suggest.Closest("GausianBlur", steps))
+	fmt.Printf("%q\n", suggest.Closest("Sboel", steps)) // a swap is one edit
+	fmt.Printf("%q\n", suggest.Closest("Threshold", steps))
+	// Output:
+	// "GaussianBlur"
+	// "Sobel"
+	// ""
+}
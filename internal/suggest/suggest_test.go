@@ -0,0 +1,42 @@
+package suggest
+
+import "testing"
+
+// Short names allow two edits, so a prefix of a long name is no match.
+func TestClosestLimit(t *testing.T) {
+	params := []string{"sigma_color", "sigma_space"}
+	if got := Closest("sigma", params); got != "" {
+		t.Errorf("Closest(%q) = %q, want no suggestion", "sigma", got)
+	}
+	if got := Closest("sigam_space", params); got != "sigma_space" {
+		t.Errorf("Closest(%q) = %q, want %q", "sigam_space", got, "sigma_space")
+	}
+	if got := Closest("Canny", nil); got != "" {
+		t.Errorf("Closest without candidates = %q", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"abc", "abd", 1},
+		{"abc", "ab", 1},
+		{"abc", "bac", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3}, // optimal string alignment, not full Damerau-Levenshtein
+		{"größe", "grösse", 2},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
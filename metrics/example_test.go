@@ -0,0 +1,37 @@
+package metrics_test
+
+import (
+	"os"
+
+	"github.com/Elliot727/gocvkit/metrics"
+)
+
+func ExampleRegistry_WriteTo() {
+	r := metrics.NewRegistry()
+	dropped := r.CounterVec("frames_dropped_total", "Frames dropped.", "reason")
+	dropped.With("sync").Inc()
+	dropped.With("queue").Add(2)
+	r.GaugeFunc("stream_clients", "Connected stream clients.", func() float64 { return 1 })
+
+	latency := r.Histogram("step_seconds", "Step latency.", []float64{1, 0.1})
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(5)
+
+	r.WriteTo(os.Stdout)
+	// Output:
+	// # HELP frames_dropped_total Frames dropped.
+	// # TYPE frames_dropped_total counter
+	// frames_dropped_total{reason="queue"} 2
+	// frames_dropped_total{reason="sync"} 1
+	// # HELP stream_clients Connected stream clients.
+	// # TYPE stream_clients gauge
+	// stream_clients 1
+	// # HELP step_seconds Step latency.
+	// # TYPE step_seconds histogram
+	// step_seconds_bucket{le="0.1"} 1
+	// step_seconds_bucket{le="1"} 2
+	// step_seconds_bucket{le="+Inf"} 3
+	// step_seconds_sum 5.55
+	// step_seconds_count 3
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+// Label values and help text are escaped the way the Prometheus text format
+// expects, which differs from Go's %q: tabs and non-ASCII stay as they are.
+func TestEscaping(t *testing.T) {
+	r := NewRegistry()
+	r.CounterVec("c", "line one\nback\\slash \"quoted\"", "v").With("a\\b\"c\nd é\t").Inc()
+
+	var b strings.Builder
+	n, err := r.WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# HELP c line one\\nback\\\\slash \"quoted\"\n" +
+		"# TYPE c counter\n" +
+		"c{v=\"a\\\\b\\\"c\\nd é\t\"} 1\n"
+	if b.String() != want {
+		t.Errorf("WriteTo() =\n%s\nwant\n%s", b.String(), want)
+	}
+	if n != int64(b.Len()) {
+		t.Errorf("WriteTo() = %d bytes, wrote %d", n, b.Len())
+	}
+}
+
+func TestWithWrongLabelCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("With() with a missing label value did not panic")
+		}
+	}()
+	NewRegistry().CounterVec("c", "h", "a", "b").With("x")
+}
@@ -7,6 +7,15 @@
 // Pipelines are safe to replace at runtime (via config hot-reload) because the
 // buffers belong to the Pipeline instance and are closed only after the old
 // pipeline is no longer in use.
+//
+// A pipeline can also branch: a stage may store its output under a name (a tap),
+// read its input from a named buffer instead of the previous stage, and merge
+// steps combine the running frame with a named buffer. The tap buffers are
+// pre-allocated and owned by the pipeline just like bufA and bufB. The name
+// "source" always refers to the original input frame.
+//
+// For multi-camera apps, RunViews starts with a step that combines one frame
+// per camera (processor.MultiView) and continues like a single-frame run.
 package pipeline
 
 import (
@@ -14,6 +23,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/Elliot727/gocvkit/frame"
 	"github.com/Elliot727/gocvkit/processor"
 
 	"gocv.io/x/gocv"
@@ -27,25 +37,67 @@ type StepStats struct {
 	MaxTime   time.Duration
 }
 
+// Source is the reserved buffer name for the unmodified input frame.
+const Source = "source"
+
+// Stage binds a processing step to the named buffers it reads and writes.
+// The zero values of Input, Tap and With give the classic linear behaviour.
+type Stage struct {
+	Step  processor.Step // Step is the processor to run
+	Input string         // Input names the buffer to read ("" = previous stage's output)
+	Tap   string         // Tap also stores the stage's output under this name
+	With  string         // With names the second operand passed to a processor.Merger
+}
+
 // Pipeline holds an ordered list of processing steps and two reusable buffers.
 type Pipeline struct {
-	Steps []processor.Step // Steps contains the ordered list of processing steps to execute
-	bufA  gocv.Mat         // bufA is the first internal scratch buffer for double-buffering
-	bufB  gocv.Mat         // bufB is the second internal scratch buffer for double-buffering
-	stats []StepStats
+	Steps  []processor.Step     // Steps contains the ordered list of processing steps to execute
+	stages []Stage              // stages holds the steps together with their routing
+	bufA   gocv.Mat             // bufA is the first internal scratch buffer for double-buffering
+	bufB   gocv.Mat             // bufB is the second internal scratch buffer for double-buffering
+	taps   map[string]*gocv.Mat // taps holds the named buffers written by stages with a Tap
+	joined gocv.Mat             // joined is the output of the multi-view step in RunViews
+	stats  []StepStats
+	merged bool // merged is set once stats went into another pipeline's report
+
+	observe func(step string, elapsed time.Duration) // observe receives every step timing (may be nil)
 }
 
-// New creates a new pipeline from a slice of processing steps.
+// New creates a new linear pipeline from a slice of processing steps.
 // The two internal buffers are pre-allocated and reused for the lifetime of the pipeline.
 func New(steps []processor.Step) *Pipeline {
+	stages := make([]Stage, len(steps))
+	for i, step := range steps {
+		stages[i] = Stage{Step: step}
+	}
+	return NewStaged(stages)
+}
+
+// NewStaged creates a pipeline whose stages may read from and write to named buffers.
+// Routing is assumed to be valid (builder.Build checks it); one buffer is
+// pre-allocated per tap name.
+func NewStaged(stages []Stage) *Pipeline {
+	steps := make([]processor.Step, len(stages))
+	taps := make(map[string]*gocv.Mat)
+	for i, st := range stages {
+		steps[i] = st.Step
+		if st.Tap != "" && taps[st.Tap] == nil {
+			m := gocv.NewMat()
+			taps[st.Tap] = &m
+		}
+	}
 	stats := make([]StepStats, len(steps))
 	for i, step := range steps {
 		stats[i] = StepStats{Name: step.Name()}
 	}
 	return &Pipeline{
-		Steps: steps,
-		bufA:  gocv.NewMat(),
-		bufB:  gocv.NewMat(),
+		Steps:  steps,
+		stages: stages,
+		bufA:   gocv.NewMat(),
+		bufB:   gocv.NewMat(),
+		taps:   taps,
+		joined: gocv.NewMat(),
+		stats:  stats,
 	}
 }
 
@@ -55,11 +107,62 @@ func (p *Pipeline) Close() {
 	p.printReport()
 	p.bufA.Close()
 	p.bufB.Close()
+	p.joined.Close()
+	for _, m := range p.taps {
+		m.Close()
+	}
 	for _, step := range p.Steps {
 		step.Close()
 	}
 }
 
+// Merge adds the step statistics of replicas, copies of p run in parallel,
+// to p's, so that p's report at Close covers them all. The replicas no longer
+// print a report of their own.
+func (p *Pipeline) Merge(replicas ...*Pipeline) {
+	for _, r := range replicas {
+		for i := range p.stats {
+			if i >= len(r.stats) {
+				break
+			}
+			s := r.stats[i]
+			p.stats[i].Calls += s.Calls
+			p.stats[i].TotalTime += s.TotalTime
+			if s.MaxTime > p.stats[i].MaxTime {
+				p.stats[i].MaxTime = s.MaxTime
+			}
+		}
+		r.merged = true
+	}
+}
+
+// SetObserver registers fn to be called with the name and duration of every
+// step execution, e.g. to feed live metrics. It must be set before the first Run.
+func (p *Pipeline) SetObserver(fn func(step string, elapsed time.Duration)) {
+	p.observe = fn
+}
+
+// Sequential reports whether any step keeps state across frames, in which case
+// the pipeline must not be replicated for parallel execution.
+func (p *Pipeline) Sequential() bool {
+	for _, step := range p.Steps {
+		if processor.IsSequential(step) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tap returns the named buffer as written during the most recent Run.
+// The Mat is owned by the pipeline and is only valid until the next Run.
+func (p *Pipeline) Tap(name string) (gocv.Mat, bool) {
+	m, ok := p.taps[name]
+	if !ok || m.Empty() {
+		return gocv.Mat{}, false
+	}
+	return *m, true
+}
+
 // Run executes the full pipeline on src and writes the final result to dst.
 //
 // The function uses zero-allocation double-buffering: each step alternately
@@ -67,6 +170,48 @@ func (p *Pipeline) Close() {
 // modified and can be safely reused or closed by the caller.
 // Run executes the full pipeline with profiling.
 func (p *Pipeline) Run(src gocv.Mat, dst *gocv.Mat) error {
+	return p.RunMeta(nil, src, dst)
+}
+
+// RunMeta is like Run but also hands meta to every step that implements
+// processor.MetaProcessable, so steps can pass results to later steps and to
+// the caller. A nil meta behaves exactly like Run.
+func (p *Pipeline) RunMeta(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat) error {
+	return p.run(meta, src, dst, 0)
+}
+
+// RunViews runs a multi-camera pipeline. Its first step, a
+// processor.MultiView, combines the views (one frame per camera) into one
+// frame, which the remaining steps then process as in RunMeta; for them the
+// name "source" refers to the combined frame. The views are not modified.
+func (p *Pipeline) RunViews(meta *frame.Meta, views []gocv.Mat, dst *gocv.Mat) error {
+	if len(p.stages) == 0 {
+		return fmt.Errorf("pipeline has no multi-view step")
+	}
+	st := p.stages[0]
+	mv, ok := st.Step.(processor.MultiView)
+	if !ok {
+		return fmt.Errorf("step %s cannot combine camera views; the first sync step must be a multi-view step such as Tile", st.Step.Name())
+	}
+	p.initStats()
+
+	start := time.Now()
+	if err := mv.ProcessViews(views, &p.joined); err != nil {
+		return fmt.Errorf("step %s failed: %w", st.Step.Name(), err)
+	}
+	if p.joined.Empty() {
+		return fmt.Errorf("step %s produced an empty output matrix; pipeline halted to prevent crash", st.Step.Name())
+	}
+	if st.Tap != "" {
+		p.joined.CopyTo(p.taps[st.Tap])
+	}
+	p.record(0, time.Since(start))
+
+	return p.run(meta, p.joined, dst, 1)
+}
+
+// run executes the stages from index from onwards on src.
+func (p *Pipeline) run(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat, from int) error {
 	if src.Ptr() == nil {
 		return nil
 	}
@@ -87,28 +232,43 @@ func (p *Pipeline) Run(src gocv.Mat, dst *gocv.Mat) error {
 		p.bufB = gocv.NewMatWithSize(src.Rows(), src.Cols(), src.Type())
 	}
 
-	if len(p.Steps) == 0 {
+	if from >= len(p.stages) {
 		src.CopyTo(dst)
 		return nil
 	}
 
-	if len(p.stats) != len(p.Steps) {
-		p.stats = make([]StepStats, len(p.Steps))
-		for i, step := range p.Steps {
-			p.stats[i] = StepStats{Name: step.Name()}
-		}
-	}
+	p.initStats()
 
 	src.CopyTo(&p.bufA)
 	in := &p.bufA
-	out := &p.bufB
 
-	for i, step := range p.Steps {
+	for i := from; i < len(p.stages); i++ {
+		st := p.stages[i]
+		step := st.Step
 		start := time.Now()
+
+		if st.Input != "" {
+			in = p.buffer(st.Input, &src)
+		}
+		// Write to whichever ping-pong buffer the input is not using.
+		out := &p.bufA
+		if in == out {
+			out = &p.bufB
+		}
+
 		if in.Empty() {
 			return fmt.Errorf("step %s: input mat is empty before processing", step.Name())
 		}
-		if err := step.Process(*in, out); err != nil {
+
+		var err error
+		if m, ok := step.(processor.Merger); ok && st.With != "" {
+			err = m.Merge(*in, *p.buffer(st.With, &src), out)
+		} else if mp, ok := step.(processor.MetaProcessable); ok && meta != nil {
+			err = mp.ProcessMeta(meta, *in, out)
+		} else {
+			err = step.Process(*in, out)
+		}
+		if err != nil {
 			return fmt.Errorf("step %s failed: %w", step.Name(), err)
 		}
 
@@ -116,23 +276,54 @@ func (p *Pipeline) Run(src gocv.Mat, dst *gocv.Mat) error {
 			return fmt.Errorf("step %s produced an empty output matrix; pipeline halted to prevent crash", step.Name())
 		}
 
-		elapsed := time.Since(start)
-
-		p.stats[i].Calls++
-		p.stats[i].TotalTime += elapsed
-		if elapsed > p.stats[i].MaxTime {
-			p.stats[i].MaxTime = elapsed
+		if st.Tap != "" {
+			out.CopyTo(p.taps[st.Tap])
 		}
 
-		in, out = out, in
+		p.record(i, time.Since(start))
+
+		in = out
 	}
 
 	in.CopyTo(dst)
 	return nil
 }
 
+// initStats re-sizes the per-step statistics if Steps was changed after New.
+func (p *Pipeline) initStats() {
+	if len(p.stats) != len(p.Steps) {
+		p.stats = make([]StepStats, len(p.Steps))
+		for i, step := range p.Steps {
+			p.stats[i] = StepStats{Name: step.Name()}
+		}
+	}
+}
+
+// record adds one execution of step i to the statistics and the observer.
+func (p *Pipeline) record(i int, elapsed time.Duration) {
+	p.stats[i].Calls++
+	p.stats[i].TotalTime += elapsed
+	if elapsed > p.stats[i].MaxTime {
+		p.stats[i].MaxTime = elapsed
+	}
+	if p.observe != nil {
+		p.observe(p.Steps[i].Name(), elapsed)
+	}
+}
+
+// buffer resolves a buffer name to the original frame or a tap.
+func (p *Pipeline) buffer(name string, src *gocv.Mat) *gocv.Mat {
+	if name == Source {
+		return src
+	}
+	return p.taps[name]
+}
+
 // printReport outputs a formatted table to stderr.
 func (p *Pipeline) printReport() {
+	if p.merged {
+		return
+	}
 	if len(p.stats) == 0 {
 		fmt.Fprintln(os.Stderr, "\n--- Pipeline Performance Report ---")
 		fmt.Fprintln(os.Stderr, "No steps executed. Pipeline was empty.")
@@ -0,0 +1,103 @@
+package pipeline
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/Elliot727/gocvkit/frame"
+	"github.com/Elliot727/gocvkit/processor"
+
+	"gocv.io/x/gocv"
+)
+
+// nop is a step that copies its input.
+type nop struct{ name string }
+
+func (n nop) Process(src gocv.Mat, dst *gocv.Mat) error { src.CopyTo(dst); return nil }
+func (n nop) Name() string                              { return n.name }
+func (n nop) Close()                                    {}
+
+// detector reports one detection per frame.
+type detector struct{ nop }
+
+func (d detector) ProcessMeta(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat) error {
+	meta.AddDetection(frame.Detection{Label: "box", Box: image.Rect(1, 2, 3, 4)})
+	meta.SetTag("found", len(meta.Detections))
+	return d.Process(src, dst)
+}
+
+// counter notes how many detections the steps before it found.
+type counter struct {
+	nop
+	seen *[]int
+}
+
+func (c counter) ProcessMeta(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat) error {
+	*c.seen = append(*c.seen, len(meta.Detections))
+	return c.Process(src, dst)
+}
+
+func TestRunMeta(t *testing.T) {
+	var seen []int
+	p := New([]processor.Step{detector{nop{"detect"}}, nop{"copy"}, counter{nop{"count"}, &seen}})
+	defer p.Close()
+
+	src := gocv.NewMatWithSize(8, 8, gocv.MatTypeCV8UC3)
+	defer src.Close()
+	dst := gocv.NewMat()
+	defer dst.Close()
+
+	meta := frame.New(7)
+	if err := p.RunMeta(meta, src, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1 || seen[0] != 1 {
+		t.Errorf("the later step saw %v detections, want [1]", seen)
+	}
+	if len(meta.Detections) != 1 || meta.Detections[0].Label != "box" {
+		t.Errorf("detections = %+v, want the box", meta.Detections)
+	}
+	if v, _ := meta.Tag("found"); v != 1 {
+		t.Errorf("tag found = %v, want 1", v)
+	}
+	if meta.Index != 7 {
+		t.Errorf("index = %d, want 7", meta.Index)
+	}
+
+	// Without metadata the same steps run as plain Processables.
+	if err := p.Run(src, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1 {
+		t.Errorf("Run called ProcessMeta: seen %v", seen)
+	}
+	if dst.Empty() {
+		t.Error("Run produced no output")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	steps := func() []processor.Step { return []processor.Step{nop{"a"}, nop{"b"}} }
+	p, r1, r2 := New(steps()), New(steps()), New(steps())
+	defer p.Close()
+	defer r1.Close()
+	defer r2.Close()
+
+	p.record(0, 2*time.Millisecond)
+	r1.record(0, 5*time.Millisecond)
+	r1.record(1, time.Millisecond)
+	r2.record(0, 3*time.Millisecond)
+
+	p.Merge(r1, r2)
+
+	if s := p.stats[0]; s.Calls != 3 || s.TotalTime != 10*time.Millisecond || s.MaxTime != 5*time.Millisecond {
+		t.Errorf("step a: %+v, want 3 calls, 10ms total, 5ms max", s)
+	}
+	if s := p.stats[1]; s.Name != "b" || s.Calls != 1 {
+		t.Errorf("step b: %+v, want 1 call", s)
+	}
+	if !r1.merged || !r2.merged || p.merged {
+		t.Error("only the replicas should be marked as merged")
+	}
+}
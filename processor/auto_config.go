@@ -8,9 +8,13 @@ package processor
 import (
 	"bytes"
 	"fmt"
+	"log"
 	"reflect"
+	"strings"
 
 	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/frame"
+	"github.com/Elliot727/gocvkit/internal/suggest"
 
 	"github.com/BurntSushi/toml"
 	"gocv.io/x/gocv"
@@ -35,6 +39,44 @@ func (a *autoWrapper) Process(src gocv.Mat, dst *gocv.Mat) error {
 	return a.impl.Process(src, dst)
 }
 
+// ProcessMeta forwards to the implementation's ProcessMeta when it has one,
+// and falls back to its plain Process otherwise.
+func (a *autoWrapper) ProcessMeta(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat) error {
+	if mp, ok := a.impl.(MetaProcessable); ok {
+		return mp.ProcessMeta(meta, src, dst)
+	}
+	return a.impl.Process(src, dst)
+}
+
+// Sequential reports whether the implementation declares itself sequential-only.
+func (a *autoWrapper) Sequential() bool {
+	s, ok := a.impl.(Sequential)
+	return ok && s.Sequential()
+}
+
+// mergeWrapper is an autoWrapper whose implementation also satisfies Merger.
+// It is a separate type so that only merge steps report themselves as Mergers.
+type mergeWrapper struct {
+	*autoWrapper
+	merger Merger
+}
+
+// Merge combines src with the named buffer other and writes the result to dst.
+func (m *mergeWrapper) Merge(src gocv.Mat, other gocv.Mat, dst *gocv.Mat) error {
+	return m.merger.Merge(src, other, dst)
+}
+
+// viewsWrapper is an autoWrapper whose implementation also satisfies MultiView.
+type viewsWrapper struct {
+	*autoWrapper
+	views MultiView
+}
+
+// ProcessViews combines the frames of several cameras into dst.
+func (v *viewsWrapper) ProcessViews(views []gocv.Mat, dst *gocv.Mat) error {
+	return v.views.ProcessViews(views, dst)
+}
+
 func (a *autoWrapper) Close() {
 	// Check if the underlying struct has a Close() method
 	if c, ok := a.impl.(interface{ Close() }); ok {
@@ -71,9 +113,19 @@ func AutoConfig(defaults Processable) Factory {
 			}
 
 			// Decode that TOML into the specific struct fields
-			if _, err := toml.Decode(buf.String(), newStepPtr.Interface()); err != nil {
+			md, err := toml.Decode(buf.String(), newStepPtr.Interface())
+			if err != nil {
 				return nil, fmt.Errorf("invalid parameters for processor %q: %w", cfg.Name, err)
 			}
+
+			// Catch typos: a key the struct has no field for would otherwise
+			// silently leave the default in place.
+			if err := unknownParams(md.Undecoded(), val); err != nil {
+				if !cfg.Permissive {
+					return nil, fmt.Errorf("invalid parameters for processor %q: %w", cfg.Name, err)
+				}
+				log.Printf("⚠️  Processor %q: %v (ignored: permissive mode)", cfg.Name, err)
+			}
 		}
 		step := newStepPtr.Interface()
 
@@ -85,9 +137,78 @@ func AutoConfig(defaults Processable) Factory {
 		proc, _ := step.(Processable)
 
 		// 3. Return the wrapped Step
-		return &autoWrapper{
+		w := &autoWrapper{
 			name: cfg.Name, // Use the name from the config file
 			impl: proc,
-		}, nil
+		}
+		if m, ok := proc.(Merger); ok {
+			return &mergeWrapper{autoWrapper: w, merger: m}, nil
+		}
+		if mv, ok := proc.(MultiView); ok {
+			return &viewsWrapper{autoWrapper: w, views: mv}, nil
+		}
+		return w, nil
+	}
+}
+
+// unknownParams reports step parameters that match no field of the processor
+// struct val, suggesting the closest valid key for each. A key inside a table
+// parameter is checked against the fields of that table.
+func unknownParams(keys []toml.Key, val reflect.Value) error {
+	undecoded := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		undecoded[key.String()] = true
+	}
+
+	var msgs []string
+	for _, key := range keys {
+		parent := key[:len(key)-1]
+		if len(parent) > 0 && undecoded[parent.String()] {
+			continue // inside a table parameter that is itself unknown
+		}
+
+		var valid []string
+		if v, ok := paramAt(val, parent); ok {
+			for _, p := range params(v) {
+				valid = append(valid, p.Key)
+			}
+		}
+		last := key[len(key)-1]
+		msg := fmt.Sprintf("unknown parameter %q", key.String())
+		if s := suggest.Closest(last, valid); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		} else if len(valid) == 0 && len(parent) == 0 {
+			msg += " (this processor takes no parameters)"
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
+
+// paramAt returns the struct that the table parameter at path decodes into,
+// starting from the processor struct val, or false if path does not lead to
+// a struct.
+func paramAt(val reflect.Value, path toml.Key) (reflect.Value, bool) {
+	for _, k := range path {
+		if val.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		found := false
+		for _, p := range params(val) {
+			if p.Key == k {
+				val, found = val.FieldByName(p.Name), true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, false
+		}
+		if val.Kind() == reflect.Pointer {
+			val = reflect.New(val.Type().Elem()).Elem()
+		}
 	}
+	return val, val.Kind() == reflect.Struct
 }
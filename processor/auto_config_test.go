@@ -0,0 +1,72 @@
+package processor_test
+
+import (
+	"strings"
+	"testing"
+
+	"gocv.io/x/gocv"
+
+	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/processor"
+)
+
+// tableStep has a table parameter, which none of the built-ins do.
+type tableStep struct {
+	Kernel struct {
+		Width  int `toml:"width"`
+		Height int `toml:"height"`
+	} `toml:"kernel"`
+}
+
+func (tableStep) Process(src gocv.Mat, dst *gocv.Mat) error {
+	src.CopyTo(dst)
+	return nil
+}
+
+func TestAutoConfigUnknownParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory processor.Factory
+		params  map[string]interface{}
+		want    string
+	}{
+		{
+			name:    "typo",
+			factory: mustGet(t, "GaussianBlur"),
+			params:  map[string]interface{}{"sigam": 2.0},
+			want:    `unknown parameter "sigam" (did you mean "sigma"?)`,
+		},
+		{
+			name:    "typo in table",
+			factory: processor.AutoConfig(&tableStep{}),
+			params:  map[string]interface{}{"kernel": map[string]interface{}{"widht": 3}},
+			want:    `unknown parameter "kernel.widht" (did you mean "width"?)`,
+		},
+		{
+			name:    "unknown table",
+			factory: processor.AutoConfig(&tableStep{}),
+			params:  map[string]interface{}{"kernal": map[string]interface{}{"width": 3}},
+			want:    `unknown parameter "kernal" (did you mean "kernel"?)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.factory(config.StepConfig{Name: tt.name, Params: tt.params})
+			if err == nil {
+				t.Fatal("no error for an unknown parameter")
+			}
+			if !strings.HasSuffix(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to end with %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func mustGet(t *testing.T, name string) processor.Factory {
+	t.Helper()
+	f, ok := processor.Get(name)
+	if !ok {
+		t.Fatalf("%s is not registered", name)
+	}
+	return f
+}
@@ -13,9 +13,9 @@ import (
 
 // Bilateral defines the configuration for bilateral filtering.
 type Bilateral struct {
-	Diameter   int     `toml:"diameter"`    // Diameter is the diameter of each pixel neighborhood
-	SigmaColor float64 `toml:"sigma_color"` // SigmaColor is the filter sigma in the color space
-	SigmaSpace float64 `toml:"sigma_space"` // SigmaSpace is the filter sigma in the coordinate space
+	Diameter   int     `toml:"diameter" range:"0," doc:"Diameter of each pixel neighbourhood (0 = derived from sigma_space)"` // Diameter is the diameter of each pixel neighborhood
+	SigmaColor float64 `toml:"sigma_color" range:"0," doc:"How different colours may be and still be mixed (> 0)"`            // SigmaColor is the filter sigma in the color space
+	SigmaSpace float64 `toml:"sigma_space" range:"0," doc:"How far apart pixels may be and still be mixed (> 0)"`             // SigmaSpace is the filter sigma in the coordinate space
 }
 
 // Validate checks constraints before the pipeline starts.
@@ -13,8 +13,8 @@ import (
 
 // GaussianBlur performs Gaussian filtering with configurable kernel and sigma.
 type GaussianBlur struct {
-	Kernel int     `toml:"kernel"` // Kernel is the size of the Gaussian kernel (will be made odd if even)
-	Sigma  float64 `toml:"sigma"`  // Sigma is the standard deviation for Gaussian kernel
+	Kernel int     `toml:"kernel" range:"1," doc:"Kernel size in pixels (made odd if even)"`                    // Kernel is the size of the Gaussian kernel (will be made odd if even)
+	Sigma  float64 `toml:"sigma" range:"0," doc:"Standard deviation of the kernel (0 = derived from its size)"` // Sigma is the standard deviation for Gaussian kernel
 }
 
 // Validate checks constraints before the pipeline starts.
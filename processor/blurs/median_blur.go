@@ -12,7 +12,7 @@ import (
 
 // MedianBlur performs median filtering with a configurable kernel size.
 type MedianBlur struct {
-	K int `toml:"k"` // K is the kernel size for median blur (will be made odd if even)
+	K int `toml:"k" range:"1," doc:"Kernel size in pixels (made odd if even)"` // K is the kernel size for median blur (will be made odd if even)
 }
 
 // Validate checks constraints before the pipeline starts.
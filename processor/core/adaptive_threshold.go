@@ -12,9 +12,9 @@ import (
 
 // Adaptive defines the configuration for adaptive thresholding.
 type Adaptive struct {
-	MaxValue  float32 `toml:"max_value"`  // MaxValue is the maximum value to use with the threshold
-	BlockSize int     `toml:"block_size"` // BlockSize is the size of the pixel neighborhood for adaptive thresholding
-	C         float32 `toml:"c"`          // C is the constant subtracted from the mean or weighted mean
+	MaxValue  float32 `toml:"max_value" range:"0," doc:"Value given to pixels above the threshold (> 0)"`                             // MaxValue is the maximum value to use with the threshold
+	BlockSize int     `toml:"block_size" range:"3," doc:"Size of the neighbourhood the threshold is computed over (odd, at least 3)"` // BlockSize is the size of the pixel neighborhood for adaptive thresholding
+	C         float32 `toml:"c" doc:"Constant subtracted from the neighbourhood mean (may be negative)"`                              // C is the constant subtracted from the mean or weighted mean
 }
 
 // Validate checks constraints before the pipeline starts.
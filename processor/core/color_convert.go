@@ -12,7 +12,7 @@ import (
 
 // ColorConvert defines the configuration for color space conversion.
 type ColorConvert struct {
-	Code     string                   `toml:"code"` // Code specifies the color conversion code (e.g., "BGR2GRAY", "BGR2HSV", "HSV2BGR", etc.)
+	Code     string                   `toml:"code" doc:"Conversion such as BGR2GRAY, BGR2HSV or HSV2BGR"` // Code specifies the color conversion code (e.g., "BGR2GRAY", "BGR2HSV", "HSV2BGR", etc.)
 	codeEnum gocv.ColorConversionCode // Pre-calculated enum
 
 }
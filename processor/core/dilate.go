@@ -14,8 +14,8 @@ import (
 
 // Dilate defines the configuration for morphological dilation.
 type Dilate struct {
-	KernelSize int `toml:"kernel"`     // KernelSize is the size of the structuring element for dilation
-	Iterations int `toml:"iterations"` // Iterations is the number of times dilation is applied
+	KernelSize int `toml:"kernel" range:"1," doc:"Size of the square structuring element"` // KernelSize is the size of the structuring element for dilation
+	Iterations int `toml:"iterations" range:"1," doc:"Times dilation is applied"`          // Iterations is the number of times dilation is applied
 	// Pre-allocated kernel to avoid recreation every frame
 	kernel gocv.Mat
 }
@@ -14,8 +14,8 @@ import (
 
 // Erode defines the configuration for morphological erosion.
 type Erode struct {
-	KernelSize int `toml:"kernel"`     // KernelSize is the size of the structuring element for erosion
-	Iterations int `toml:"iterations"` // Iterations is the number of times erosion is applied
+	KernelSize int `toml:"kernel" range:"1," doc:"Size of the square structuring element"` // KernelSize is the size of the structuring element for erosion
+	Iterations int `toml:"iterations" range:"1," doc:"Times erosion is applied"`           // Iterations is the number of times erosion is applied
 	// Pre-allocated resources
 	kernel gocv.Mat
 }
@@ -12,7 +12,7 @@ import (
 
 // Flip defines the configuration for image flipping.
 type Flip struct {
-	Mode     string `toml:"mode"` // Mode specifies the flip direction: "horizontal", "vertical", or "both"
+	Mode     string `toml:"mode" doc:"Flip direction: horizontal, vertical or both"` // Mode specifies the flip direction: "horizontal", "vertical", or "both"
 	modeCode int
 }
 
@@ -13,8 +13,8 @@ import (
 
 // MorphClose defines the configuration for morphological close operation.
 type MorphClose struct {
-	KernelSize int `toml:"kernel"`     // KernelSize is the size of the structuring element for morphological close
-	Iterations int `toml:"iterations"` // Iterations is the number of times morphological close is applied
+	KernelSize int `toml:"kernel" range:"1," doc:"Size of the square structuring element"` // KernelSize is the size of the structuring element for morphological close
+	Iterations int `toml:"iterations" range:"1," doc:"Times the close is applied"`         // Iterations is the number of times morphological close is applied
 	kernel     gocv.Mat
 	temp       gocv.Mat
 }
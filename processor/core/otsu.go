@@ -13,8 +13,8 @@ import (
 
 // Otsu defines the configuration for Otsu thresholding.
 type Otsu struct {
-	MaxValue float32 `toml:"max_value"` // MaxValue is the maximum value to use with the threshold
-	Invert   bool    `toml:"invert"`    // Invert indicates whether to invert the threshold result
+	MaxValue float32 `toml:"max_value" range:"0," doc:"Value given to pixels above the threshold (> 0)"` // MaxValue is the maximum value to use with the threshold
+	Invert   bool    `toml:"invert" doc:"Swap foreground and background"`                                // Invert indicates whether to invert the threshold result
 	flags    gocv.ThresholdType
 }
 
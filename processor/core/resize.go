@@ -13,8 +13,8 @@ import (
 
 // Resize defines the configuration for image resizing.
 type Resize struct {
-	Width  int `toml:"width"`  // Width is the target width for the resized image
-	Height int `toml:"height"` // Height is the target height for the resized image
+	Width  int `toml:"width" range:"1," doc:"Target width in pixels"`   // Width is the target width for the resized image
+	Height int `toml:"height" range:"1," doc:"Target height in pixels"` // Height is the target height for the resized image
 }
 
 func (r *Resize) Validate() error {
@@ -12,7 +12,7 @@ import (
 
 // Rotate defines the configuration for image rotation.
 type Rotate struct {
-	Angle       float64 `toml:"angle"` // Angle is the rotation angle in degrees (90, 180, 270 for optimized rotations)
+	Angle       float64 `toml:"angle" doc:"Rotation in degrees (90, 180 and 270 are fastest)"` // Angle is the rotation angle in degrees (90, 180, 270 for optimized rotations)
 	isOptimized bool
 	optCode     gocv.RotateFlag
 	hasMatrix   bool
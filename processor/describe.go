@@ -0,0 +1,130 @@
+package processor
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Param describes one configurable parameter of a processor.
+//
+// Besides the `toml` key, a field may carry two optional tags used purely for
+// documentation and UIs:
+//
+//	Alpha float64 `toml:"alpha" range:"0,1" doc:"Weight of the running frame"`
+//
+// Either side of a range may be left empty for an open bound (`range:"1,"`).
+// Every parameter of the built-in processors has a doc tag, and a range
+// wherever its value is bounded.
+type Param struct {
+	Name    string      `json:"name"`          // Name is the Go field name
+	Key     string      `json:"key"`           // Key is the TOML key used in [[pipeline.steps]]
+	Type    string      `json:"type"`          // Type is the Go type of the field (e.g. "float64")
+	Default interface{} `json:"default"`       // Default is the value from the registered default struct
+	Doc     string      `json:"doc,omitempty"` // Doc is the field's `doc` tag
+	Min     *float64    `json:"min,omitempty"` // Min is the inclusive lower bound from the `range` tag
+	Max     *float64    `json:"max,omitempty"` // Max is the inclusive upper bound from the `range` tag
+}
+
+// Info describes a registered processor.
+type Info struct {
+	Name       string  `json:"name"`       // Name is the name used in config files
+	Params     []Param `json:"params"`     // Params lists the parameters, in struct field order
+	Merge      bool    `json:"merge"`      // Merge is true for steps that need a second buffer (`with`)
+	MultiView  bool    `json:"multi_view"` // MultiView is true for steps that combine several cameras ([[sync.steps]])
+	Sequential bool    `json:"sequential"` // Sequential is true for steps that must see every frame in order
+	Custom     bool    `json:"custom"`     // Custom is true for processors registered with a Factory func, whose parameters are unknown
+}
+
+// List returns the names of all registered processors, sorted.
+func List() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Describe returns the parameter schema of a registered processor, reflected
+// from the default struct it was registered with.
+func Describe(name string) (Info, bool) {
+	e, ok := registry[name]
+	if !ok {
+		return Info{}, false
+	}
+
+	info := Info{Name: name, Params: []Param{}}
+	if e.defaults == nil {
+		info.Custom = true
+		return info, true
+	}
+
+	_, info.Merge = e.defaults.(Merger)
+	_, info.MultiView = e.defaults.(MultiView)
+	if s, ok := e.defaults.(Sequential); ok {
+		info.Sequential = s.Sequential()
+	}
+
+	val := reflect.ValueOf(e.defaults)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return info, true
+	}
+
+	info.Params = params(val)
+	return info, true
+}
+
+// params reflects the configurable fields of a struct value. It mirrors how
+// the TOML decoder maps keys: exported fields only, `toml:"-"` skipped, the
+// field name used when there is no tag, and anonymous structs flattened.
+func params(val reflect.Value) []Param {
+	var out []Param
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		key, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+		if key == "-" {
+			continue
+		}
+		if f.Anonymous && key == "" && f.Type.Kind() == reflect.Struct {
+			out = append(out, params(val.Field(i))...)
+			continue
+		}
+		if key == "" {
+			key = f.Name
+		}
+
+		p := Param{
+			Name:    f.Name,
+			Key:     key,
+			Type:    f.Type.String(),
+			Default: val.Field(i).Interface(),
+			Doc:     f.Tag.Get("doc"),
+		}
+		if r, ok := f.Tag.Lookup("range"); ok {
+			lo, hi, _ := strings.Cut(r, ",")
+			p.Min = parseBound(lo)
+			p.Max = parseBound(hi)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// parseBound parses one side of a `range` tag; an empty or invalid side is open.
+func parseBound(s string) *float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
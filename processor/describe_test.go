@@ -0,0 +1,68 @@
+package processor_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Elliot727/gocvkit/processor"
+
+	_ "github.com/Elliot727/gocvkit/processor/blurs"
+	_ "github.com/Elliot727/gocvkit/processor/core"
+	_ "github.com/Elliot727/gocvkit/processor/edges"
+	_ "github.com/Elliot727/gocvkit/processor/events"
+	_ "github.com/Elliot727/gocvkit/processor/merge"
+	_ "github.com/Elliot727/gocvkit/processor/multi"
+)
+
+// TestBuiltinsDocumented keeps the schema served at /api/processors
+// complete: every parameter of a built-in processor needs a doc tag.
+func TestBuiltinsDocumented(t *testing.T) {
+	for _, name := range processor.List() {
+		info, _ := processor.Describe(name)
+		for _, p := range info.Params {
+			if p.Doc == "" {
+				t.Errorf("%s.%s has no doc tag", name, p.Name)
+			}
+			if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+				t.Errorf("%s.%s: range %v,%v is empty", name, p.Name, *p.Min, *p.Max)
+			}
+		}
+	}
+}
+
+func TestDescribeRanges(t *testing.T) {
+	// want is the parameter's range as "min,max", with "" for an open end.
+	want := map[string]string{
+		"Blend.alpha":           "0,1",
+		"Adaptive.block_size":   "3,",
+		"Bilateral.sigma_color": "0,",
+		"Resize.width":          "1,",
+		"Rotate.angle":          ",",
+	}
+	for key, w := range want {
+		step, param, _ := strings.Cut(key, ".")
+		info, ok := processor.Describe(step)
+		if !ok {
+			t.Errorf("%s is not registered", step)
+			continue
+		}
+		i := slices.IndexFunc(info.Params, func(p processor.Param) bool { return p.Key == param })
+		if i < 0 {
+			t.Errorf("%s has no parameter %q", step, param)
+			continue
+		}
+		p := info.Params[i]
+		if got := bound(p.Min) + "," + bound(p.Max); got != w {
+			t.Errorf("%s range = %q, want %q", key, got, w)
+		}
+	}
+}
+
+func bound(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
+}
@@ -8,8 +8,8 @@ import (
 )
 
 type BackgroundSubtractor struct {
-	Algorithm    string  `toml:"algorithm"`
-	LearningRate float64 `toml:"learning_rate"`
+	Algorithm    string  `toml:"algorithm" doc:"Background model: MOG2 or KNN"`
+	LearningRate float64 `toml:"learning_rate" doc:"How fast the model adapts (-1 = automatic)"`
 
 	mog2 *gocv.BackgroundSubtractorMOG2
 	knn  *gocv.BackgroundSubtractorKNN
@@ -53,6 +53,9 @@ func (b *BackgroundSubtractor) Process(src gocv.Mat, dst *gocv.Mat) error {
 	return nil
 }
 
+// Sequential reports true: the background model must see every frame in order.
+func (b *BackgroundSubtractor) Sequential() bool { return true }
+
 func (b *BackgroundSubtractor) Close() {
 	if b.mog2 != nil {
 		b.mog2.Close()
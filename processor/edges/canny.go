@@ -12,8 +12,8 @@ import (
 
 // Canny defines the configuration for Canny edge detection filter.
 type Canny struct {
-	Low  float64 `toml:"low"`  // Low is the lower threshold for edge detection
-	High float64 `toml:"high"` // High is the upper threshold for edge detection
+	Low  float64 `toml:"low" range:"0," doc:"Lower hysteresis threshold"`  // Low is the lower threshold for edge detection
+	High float64 `toml:"high" range:"0," doc:"Upper hysteresis threshold"` // High is the upper threshold for edge detection
 }
 
 func (c *Canny) Validate() error {
@@ -13,7 +13,7 @@ import (
 
 // Laplacian defines the configuration for Laplacian edge detection filter.
 type Laplacian struct {
-	K int `toml:"k"` // K is the aperture size for the Laplacian operator
+	K int `toml:"k" range:"1," doc:"Aperture size (odd)"` // K is the aperture size for the Laplacian operator
 }
 
 func (l *Laplacian) Validate() error {
@@ -12,7 +12,7 @@ import (
 
 // Sobel performs Sobel edge detection with configurable kernel size.
 type Sobel struct {
-	K int `toml:"sobel_size"` // K is the kernel size for Sobel edge detection (will be made odd if even)
+	K int `toml:"sobel_size" range:"1," doc:"Kernel size (odd)"` // K is the kernel size for Sobel edge detection (will be made odd if even)
 }
 
 func (s *Sobel) Validate() error {
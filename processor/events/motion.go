@@ -0,0 +1,97 @@
+// Package events provides steps that watch the frames and raise frame events
+// (frame.Meta.Events), e.g. to start event clips ([clips]).
+package events
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/Elliot727/gocvkit/frame"
+	"github.com/Elliot727/gocvkit/processor"
+	"gocv.io/x/gocv"
+)
+
+// Motion compares every frame with the previous one and raises an event when
+// enough of it changed, e.g. to trigger event clips ([clips]). The frame
+// passes through unchanged; the changed fraction is tagged as "motion".
+type Motion struct {
+	Threshold float64 `toml:"threshold" range:"0,255" doc:"Grey-level change for a pixel to count as moving"`
+	MinArea   float64 `toml:"min_area" range:"0,1" doc:"Fraction of moving pixels that raises the event (above 0)"`
+	Event     string  `toml:"event" doc:"Name of the event raised on the frame"`
+
+	prev *gocv.Mat
+	gray *gocv.Mat
+	diff *gocv.Mat
+}
+
+// Validate checks constraints before the pipeline starts.
+func (m *Motion) Validate() error {
+	if m.Event == "" {
+		return fmt.Errorf("event must not be empty")
+	}
+	if m.MinArea <= 0 {
+		return fmt.Errorf("min_area must be > 0, got %f", m.MinArea)
+	}
+	return nil
+}
+
+// Process passes the frame through; the detection needs the frame's metadata.
+func (m *Motion) Process(src gocv.Mat, dst *gocv.Mat) error {
+	src.CopyTo(dst)
+	return nil
+}
+
+// ProcessMeta measures the change since the previous frame and raises the event.
+func (m *Motion) ProcessMeta(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat) error {
+	src.CopyTo(dst)
+
+	if m.prev == nil {
+		prev, gray, diff := gocv.NewMat(), gocv.NewMat(), gocv.NewMat()
+		m.prev, m.gray, m.diff = &prev, &gray, &diff
+	}
+
+	if src.Channels() == 1 {
+		src.CopyTo(m.gray)
+	} else {
+		gocv.CvtColor(src, m.gray, gocv.ColorBGRToGray)
+	}
+	// Smooth away sensor noise so it does not count as motion.
+	gocv.GaussianBlur(*m.gray, m.gray, image.Pt(5, 5), 0, 0, gocv.BorderDefault)
+
+	if m.prev.Empty() || m.prev.Rows() != m.gray.Rows() || m.prev.Cols() != m.gray.Cols() {
+		m.gray.CopyTo(m.prev)
+		return nil
+	}
+
+	gocv.AbsDiff(*m.gray, *m.prev, m.diff)
+	gocv.Threshold(*m.diff, m.diff, float32(m.Threshold), 255, gocv.ThresholdBinary)
+	m.gray.CopyTo(m.prev)
+
+	changed := float64(gocv.CountNonZero(*m.diff)) / float64(m.diff.Rows()*m.diff.Cols())
+	meta.SetTag("motion", changed)
+	if changed >= m.MinArea {
+		meta.AddEvent(m.Event)
+	}
+	return nil
+}
+
+// Sequential reports true: each frame is compared with the one before it.
+func (m *Motion) Sequential() bool { return true }
+
+// Close frees the previous frame and the scratch Mats.
+func (m *Motion) Close() {
+	for _, mat := range []*gocv.Mat{m.prev, m.gray, m.diff} {
+		if mat != nil {
+			mat.Close()
+		}
+	}
+	m.prev, m.gray, m.diff = nil, nil, nil
+}
+
+func init() {
+	processor.Register("Motion", &Motion{
+		Threshold: 25,
+		MinArea:   0.01,
+		Event:     "motion",
+	})
+}
@@ -0,0 +1,38 @@
+package merge
+
+import (
+	"github.com/Elliot727/gocvkit/processor"
+	"gocv.io/x/gocv"
+)
+
+// Add adds a named buffer to the running frame pixel by pixel, saturating at
+// 255. It is the simplest way to overlay edges or a mask on the original frame.
+type Add struct {
+	scratch *gocv.Mat
+}
+
+// Process always fails: Add needs a second buffer.
+func (a *Add) Process(src gocv.Mat, dst *gocv.Mat) error {
+	return errNoOperand("Add")
+}
+
+// Merge writes src + other to dst.
+func (a *Add) Merge(src gocv.Mat, other gocv.Mat, dst *gocv.Mat) error {
+	o, err := match(src, other, scratchMat(&a.scratch))
+	if err != nil {
+		return err
+	}
+	return gocv.Add(src, o, dst)
+}
+
+// Close frees the scratch Mat.
+func (a *Add) Close() {
+	if a.scratch != nil {
+		a.scratch.Close()
+		a.scratch = nil
+	}
+}
+
+func init() {
+	processor.Register("Add", &Add{})
+}
@@ -0,0 +1,61 @@
+package merge
+
+import (
+	"image"
+
+	"github.com/Elliot727/gocvkit/processor"
+	"gocv.io/x/gocv"
+)
+
+// ApplyMask keeps the pixels of the running frame where the `with` buffer is
+// non-zero and blacks out the rest, e.g. to show only the moving parts of a
+// colour frame using a BackgroundSubtractor mask:
+//
+//	[[pipeline.steps]]
+//	name = "BackgroundSubtractor"
+//	tap = "motion"
+//
+//	[[pipeline.steps]]
+//	name = "ApplyMask"
+//	input = "source"
+//	with = "motion"
+type ApplyMask struct {
+	scratch *gocv.Mat
+}
+
+// Process always fails: ApplyMask needs a mask buffer.
+func (a *ApplyMask) Process(src gocv.Mat, dst *gocv.Mat) error {
+	return errNoOperand("ApplyMask")
+}
+
+// Merge writes src to dst wherever mask is non-zero; everything else is zero.
+func (a *ApplyMask) Merge(src gocv.Mat, mask gocv.Mat, dst *gocv.Mat) error {
+	scratch := scratchMat(&a.scratch)
+
+	// The mask must be single-channel, whatever src is.
+	if mask.Channels() == 3 {
+		gocv.CvtColor(mask, scratch, gocv.ColorBGRToGray)
+		mask = *scratch
+	}
+	if mask.Rows() != src.Rows() || mask.Cols() != src.Cols() {
+		gocv.Resize(mask, scratch, image.Pt(src.Cols(), src.Rows()), 0, 0, gocv.InterpolationNearestNeighbor)
+		mask = *scratch
+	}
+
+	// CopyTo sizes dst like src; clear it so unmasked pixels end up black.
+	src.CopyTo(dst)
+	dst.SetTo(gocv.NewScalar(0, 0, 0, 0))
+	return src.CopyToWithMask(dst, mask)
+}
+
+// Close frees the scratch Mat.
+func (a *ApplyMask) Close() {
+	if a.scratch != nil {
+		a.scratch.Close()
+		a.scratch = nil
+	}
+}
+
+func init() {
+	processor.Register("ApplyMask", &ApplyMask{})
+}
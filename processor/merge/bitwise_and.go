@@ -0,0 +1,39 @@
+package merge
+
+import (
+	"github.com/Elliot727/gocvkit/processor"
+	"gocv.io/x/gocv"
+)
+
+// BitwiseAnd computes the per-pixel bitwise AND of the running frame and a
+// named buffer, keeping only the bits set in both, e.g. to intersect two
+// binary masks.
+type BitwiseAnd struct {
+	scratch *gocv.Mat
+}
+
+// Process always fails: BitwiseAnd needs a second buffer.
+func (b *BitwiseAnd) Process(src gocv.Mat, dst *gocv.Mat) error {
+	return errNoOperand("BitwiseAnd")
+}
+
+// Merge writes src & other to dst.
+func (b *BitwiseAnd) Merge(src gocv.Mat, other gocv.Mat, dst *gocv.Mat) error {
+	o, err := match(src, other, scratchMat(&b.scratch))
+	if err != nil {
+		return err
+	}
+	return gocv.BitwiseAnd(src, o, dst)
+}
+
+// Close frees the scratch Mat.
+func (b *BitwiseAnd) Close() {
+	if b.scratch != nil {
+		b.scratch.Close()
+		b.scratch = nil
+	}
+}
+
+func init() {
+	processor.Register("BitwiseAnd", &BitwiseAnd{})
+}
@@ -0,0 +1,52 @@
+package merge
+
+import (
+	"fmt"
+
+	"github.com/Elliot727/gocvkit/processor"
+	"gocv.io/x/gocv"
+)
+
+// Blend computes a weighted sum of the running frame and a named buffer:
+// dst = alpha*src + (1-alpha)*other.
+type Blend struct {
+	Alpha float64 `toml:"alpha" range:"0,1" doc:"Weight of the running frame; the buffer gets 1-alpha"` // Alpha is the weight of the running frame (0.0–1.0); the buffer gets 1-Alpha
+
+	scratch *gocv.Mat
+}
+
+// Validate checks constraints before the pipeline starts.
+func (b *Blend) Validate() error {
+	if b.Alpha < 0 || b.Alpha > 1 {
+		return fmt.Errorf("alpha must be between 0 and 1, got %f", b.Alpha)
+	}
+	return nil
+}
+
+// Process always fails: Blend needs a second buffer.
+func (b *Blend) Process(src gocv.Mat, dst *gocv.Mat) error {
+	return errNoOperand("Blend")
+}
+
+// Merge writes alpha*src + (1-alpha)*other to dst.
+func (b *Blend) Merge(src gocv.Mat, other gocv.Mat, dst *gocv.Mat) error {
+	o, err := match(src, other, scratchMat(&b.scratch))
+	if err != nil {
+		return err
+	}
+	return gocv.AddWeighted(src, b.Alpha, o, 1-b.Alpha, 0, dst)
+}
+
+// Close frees the scratch Mat.
+func (b *Blend) Close() {
+	if b.scratch != nil {
+		b.scratch.Close()
+		b.scratch = nil
+	}
+}
+
+func init() {
+	processor.Register("Blend", &Blend{
+		Alpha: 0.5,
+	})
+}
@@ -0,0 +1,21 @@
+// Package merge provides steps that combine the running frame with a second,
+// named buffer (see the `tap` and `with` step keys).
+//
+// A typical use overlays edges on the original colour frame:
+//
+//	[[pipeline.steps]]
+//	name = "Grayscale"
+//
+//	[[pipeline.steps]]
+//	name = "Canny"
+//	tap = "edges"
+//
+//	[[pipeline.steps]]
+//	name = "Add"
+//	input = "source"
+//	with = "edges"
+//
+// The second operand is converted to the depth, size and channel count of the
+// first when they differ, so a single-channel mask can be merged with a BGR
+// frame, and a 16-bit Laplacian or Scharr tap with an 8-bit one.
+package merge
@@ -0,0 +1,68 @@
+package merge
+
+import (
+	"fmt"
+	"image"
+
+	"gocv.io/x/gocv"
+)
+
+// depthMask selects the depth bits of a gocv.MatType (CV_MAT_DEPTH_MASK).
+const depthMask gocv.MatType = 7
+
+// match returns other converted to the depth, size and channel count of src.
+// If no conversion is needed, other is returned unchanged; otherwise the
+// result is written to scratch, which the caller owns.
+func match(src, other gocv.Mat, scratch *gocv.Mat) (gocv.Mat, error) {
+	out := other
+
+	// Depth first: CvtColor does not take every depth, such as the CV_16S
+	// of Laplacian and Scharr.
+	if depth := src.Type() & depthMask; out.Type()&depthMask != depth {
+		var err error
+		if depth == gocv.MatTypeCV8U {
+			// Gradients are signed; keep their magnitude, saturated at 255.
+			err = gocv.ConvertScaleAbs(out, scratch, 1, 0)
+		} else {
+			err = out.ConvertTo(scratch, depth|gocv.MatType((out.Channels()-1)<<3))
+		}
+		if err != nil {
+			return gocv.Mat{}, err
+		}
+		out = *scratch
+	}
+
+	if out.Channels() != src.Channels() {
+		switch {
+		case out.Channels() == 1 && src.Channels() == 3:
+			gocv.CvtColor(out, scratch, gocv.ColorGrayToBGR)
+		case out.Channels() == 3 && src.Channels() == 1:
+			gocv.CvtColor(out, scratch, gocv.ColorBGRToGray)
+		default:
+			return gocv.Mat{}, fmt.Errorf("cannot merge %d-channel buffer into %d-channel frame", out.Channels(), src.Channels())
+		}
+		out = *scratch
+	}
+
+	if out.Rows() != src.Rows() || out.Cols() != src.Cols() {
+		gocv.Resize(out, scratch, image.Pt(src.Cols(), src.Rows()), 0, 0, gocv.InterpolationLinear)
+		out = *scratch
+	}
+
+	return out, nil
+}
+
+// scratchMat returns the scratch Mat *m, allocating it on first use, so a
+// merge step works however it was built.
+func scratchMat(m **gocv.Mat) *gocv.Mat {
+	if *m == nil {
+		mat := gocv.NewMat()
+		*m = &mat
+	}
+	return *m
+}
+
+// errNoOperand is returned by Process on merge steps, which only make sense with a `with` buffer.
+func errNoOperand(name string) error {
+	return fmt.Errorf("%s is a merge step and needs a second buffer (set with = \"<tap>\")", name)
+}
@@ -0,0 +1,46 @@
+package merge
+
+import (
+	"testing"
+
+	"gocv.io/x/gocv"
+)
+
+// A CV_16S tap (Laplacian, Scharr) merges into an 8-bit BGR frame.
+func TestMergeMixedDepth(t *testing.T) {
+	src := gocv.NewMatWithSizeFromScalar(gocv.NewScalar(10, 20, 30, 0), 4, 4, gocv.MatTypeCV8UC3)
+	defer src.Close()
+	grad := gocv.NewMatWithSizeFromScalar(gocv.NewScalar(-300, 0, 0, 0), 4, 4, gocv.MatTypeCV16SC1)
+	defer grad.Close()
+
+	steps := map[string]interface {
+		Merge(src, other gocv.Mat, dst *gocv.Mat) error
+		Close()
+	}{
+		"Add":        &Add{},
+		"BitwiseAnd": &BitwiseAnd{},
+		"Blend":      &Blend{Alpha: 0.5},
+	}
+	for name, step := range steps {
+		dst := gocv.NewMat()
+		if err := step.Merge(src, grad, &dst); err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if dst.Type() != gocv.MatTypeCV8UC3 {
+			t.Errorf("%s: result type %v, want CV_8UC3", name, dst.Type())
+		}
+		dst.Close()
+		step.Close()
+	}
+
+	// The gradient's magnitude saturates: Add gives 255 in every channel.
+	add := &Add{}
+	defer add.Close()
+	dst := gocv.NewMat()
+	defer dst.Close()
+	if err := add.Merge(src, grad, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if v := dst.GetVecbAt(0, 0); v[0] != 255 || v[2] != 255 {
+		t.Errorf("Add pixel = %v, want 255s", v)
+	}
+}
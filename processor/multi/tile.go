@@ -0,0 +1,103 @@
+// Package multi provides steps that combine the synchronised frames of
+// several cameras (see [[cameras]] and [sync]) into one.
+//
+// A multi-view step must be the first of the `[[sync.steps]]` pipeline; the
+// steps after it process the combined frame like any other:
+//
+//	[sync]
+//	enabled = true
+//
+//	[[sync.steps]]
+//	name = "Tile"
+//	columns = 2
+package multi
+
+import (
+	"image"
+	"math"
+
+	"github.com/Elliot727/gocvkit/processor"
+	"gocv.io/x/gocv"
+)
+
+// Tile lays the views out in a grid, in [[cameras]] order, each cell the
+// size of the first view. Grayscale views are converted to BGR so cameras
+// with different pipelines can share a canvas.
+type Tile struct {
+	Columns int `toml:"columns" range:"0," doc:"Cells per row (0 = as square a grid as fits)"`
+
+	canvas  *gocv.Mat
+	scratch *gocv.Mat
+}
+
+func (t *Tile) Validate() error {
+	if t.canvas == nil {
+		c := gocv.NewMat()
+		t.canvas = &c
+	}
+	if t.scratch == nil {
+		s := gocv.NewMat()
+		t.scratch = &s
+	}
+	return nil
+}
+
+// Process passes a single frame through: one view tiles to itself.
+func (t *Tile) Process(src gocv.Mat, dst *gocv.Mat) error {
+	src.CopyTo(dst)
+	return nil
+}
+
+// ProcessViews draws every view into its cell of the grid and writes the grid to dst.
+func (t *Tile) ProcessViews(views []gocv.Mat, dst *gocv.Mat) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	cols := t.Columns
+	if cols <= 0 {
+		cols = int(math.Ceil(math.Sqrt(float64(len(views)))))
+	}
+	cols = min(cols, len(views))
+	rows := (len(views) + cols - 1) / cols
+
+	w, h := views[0].Cols(), views[0].Rows()
+	if t.canvas.Rows() != rows*h || t.canvas.Cols() != cols*w {
+		t.canvas.Close()
+		*t.canvas = gocv.NewMatWithSize(rows*h, cols*w, gocv.MatTypeCV8UC3)
+	}
+	t.canvas.SetTo(gocv.NewScalar(0, 0, 0, 0))
+
+	for i, v := range views {
+		cell := v
+		if cell.Channels() == 1 {
+			gocv.CvtColor(cell, t.scratch, gocv.ColorGrayToBGR)
+			cell = *t.scratch
+		}
+		if cell.Cols() != w || cell.Rows() != h {
+			gocv.Resize(cell, t.scratch, image.Pt(w, h), 0, 0, gocv.InterpolationLinear)
+			cell = *t.scratch
+		}
+
+		x, y := (i%cols)*w, (i/cols)*h
+		region := t.canvas.Region(image.Rect(x, y, x+w, y+h))
+		cell.CopyTo(&region)
+		region.Close()
+	}
+
+	t.canvas.CopyTo(dst)
+	return nil
+}
+
+func (t *Tile) Close() {
+	for _, m := range []*gocv.Mat{t.canvas, t.scratch} {
+		if m != nil {
+			m.Close()
+		}
+	}
+	t.canvas, t.scratch = nil, nil
+}
+
+func init() {
+	processor.Register("Tile", &Tile{})
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/Elliot727/gocvkit/config"
+	"github.com/Elliot727/gocvkit/frame"
 
 	"gocv.io/x/gocv"
 )
@@ -18,6 +19,28 @@ type Processable interface {
 	Process(src gocv.Mat, dst *gocv.Mat) error
 }
 
+// MetaProcessable is an optional extension of Processable for steps that read
+// or produce per-frame metadata (detections, keypoints, tags). When a step
+// implements it, the pipeline calls ProcessMeta instead of Process; meta is
+// shared by every step of the frame and handed to the frame callback afterwards.
+type MetaProcessable interface {
+	ProcessMeta(meta *frame.Meta, src gocv.Mat, dst *gocv.Mat) error
+}
+
+// Sequential is implemented by steps that keep temporal state across frames
+// (background models, trackers, frame differencing). Such steps must see every
+// frame, in order, on a single instance, so pipelines containing one are never
+// replicated across parallel workers.
+type Sequential interface {
+	Sequential() bool
+}
+
+// IsSequential reports whether step declares itself sequential-only.
+func IsSequential(step Step) bool {
+	s, ok := step.(Sequential)
+	return ok && s.Sequential()
+}
+
 // Step is the internal interface used by the Pipeline.
 // It combines the user's logic with the system's need for a Name.
 type Step interface {
@@ -26,11 +49,31 @@ type Step interface {
 	Close()
 }
 
+// Merger is implemented by steps that combine the current frame with a second,
+// named buffer selected by the step's `with` key (e.g. a mask or the source frame).
+// The pipeline calls Merge instead of Process for such steps.
+type Merger interface {
+	Merge(src gocv.Mat, other gocv.Mat, dst *gocv.Mat) error
+}
+
+// MultiView is implemented by steps that combine the synchronised frames of
+// several cameras into one (tiling, stereo disparity). It can only be the
+// first step of a `[[sync.steps]]` pipeline; views are in [[cameras]] order.
+type MultiView interface {
+	ProcessViews(views []gocv.Mat, dst *gocv.Mat) error
+}
+
 // Factory is a function that creates a Step from configuration.
 type Factory func(config.StepConfig) (Step, error)
 
+// entry is one registered processor.
+type entry struct {
+	factory  Factory
+	defaults Processable // defaults is the registered struct, or nil for factory funcs
+}
+
 // registry is private to ensure thread-safety and prevent external tampering.
-var registry = make(map[string]Factory)
+var registry = make(map[string]entry)
 
 // Register adds a new processor. It is smart and accepts two types:
 // 1. A struct instance (Processable): Automatically wrapped with AutoConfig.
@@ -39,9 +82,9 @@ func Register(name string, item interface{}) {
 	switch v := item.(type) {
 	case Processable:
 		// The Magic: We auto-wrap the struct here!
-		registry[name] = AutoConfig(v)
+		registry[name] = entry{factory: AutoConfig(v), defaults: v}
 	case func(config.StepConfig) (Step, error):
-		registry[name] = v
+		registry[name] = entry{factory: v}
 	default:
 		panic(fmt.Sprintf("processor.Register: %q must be a Processable struct or a Factory func", name))
 	}
@@ -49,6 +92,6 @@ func Register(name string, item interface{}) {
 
 // Get looks up a processor factory by name.
 func Get(name string) (Factory, bool) {
-	f, ok := registry[name]
-	return f, ok
+	e, ok := registry[name]
+	return e.factory, ok
 }
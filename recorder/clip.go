@@ -0,0 +1,186 @@
+package recorder
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Elliot727/gocvkit/frame"
+
+	"gocv.io/x/gocv"
+)
+
+// Clipper records event clips: it keeps the last Pre of frames in memory
+// and, when an event fires, writes them to a new clip followed by every frame
+// until Post after the last event. Events during a clip extend it.
+//
+// Events come from the frames themselves (frame.Meta.Events, raised by steps
+// such as Motion) or from Trigger, which is safe to call from any goroutine.
+type Clipper struct {
+	rec    *Recorder
+	pre    time.Duration
+	post   time.Duration
+	events map[string]bool // events lists the frame events that start a clip (empty = any)
+
+	ring  []buffered // ring holds the frames of the last pre, oldest first
+	until time.Time  // until is when the current clip ends (zero = no clip open)
+
+	retryAt time.Time // retryAt holds back writes after the recorder failed (zero = not failing)
+
+	mu        sync.Mutex
+	triggered string // triggered is the reason passed to Trigger since the last frame
+}
+
+// retryAfter is how long clip writes are held back after the recorder
+// failed, so that a broken output is logged once rather than on every frame.
+const retryAfter = 10 * time.Second
+
+// buffered is a frame waiting in the pre-event buffer.
+type buffered struct {
+	img  gocv.Mat
+	meta *frame.Meta
+	at   time.Time
+}
+
+// NewClipper creates a clip recorder writing to path, which is named per clip
+// like a Recorder's segments (a strftime template, or numbered). Only the
+// listed frame events start a clip; none means any event does.
+func NewClipper(path string, pre, post time.Duration, events ...string) *Clipper {
+	c := &Clipper{
+		rec:    NewRecorder(path),
+		pre:    pre,
+		post:   post,
+		events: make(map[string]bool),
+	}
+	for _, e := range events {
+		c.events[e] = true
+	}
+	return c
+}
+
+// SetFPS sets the frame rate of the clips.
+func (c *Clipper) SetFPS(fps float64) { c.rec.SetFPS(fps) }
+
+// SetFormat sets the codec, container and quality of the clips.
+func (c *Clipper) SetFormat(f Format) { c.rec.SetFormat(f) }
+
+// Check tries out the clip format; see Recorder.Check.
+func (c *Clipper) Check() error { return c.rec.Check() }
+
+// SetConfig sets the configuration recorded at the top of every clip's
+// sidecar; see Recorder.SetConfig.
+func (c *Clipper) SetConfig(cfg interface{}) { c.rec.SetConfig(cfg) }
+
+// SetSidecar enables the per-frame JSON Lines file next to each clip.
+func (c *Clipper) SetSidecar(enabled bool) { c.rec.SetSidecar(enabled) }
+
+// Trigger fires an event: the next frame starts a clip, or extends the one
+// being written.
+func (c *Clipper) Trigger(reason string) {
+	c.mu.Lock()
+	c.triggered = reason
+	c.mu.Unlock()
+}
+
+// WriteMeta feeds the next output frame. img is copied when it has to be
+// buffered, so the caller keeps ownership.
+//
+// A failed write is logged and clip writes are held back for a while, but
+// frames are still buffered and checked for events, so none is missed.
+func (c *Clipper) WriteMeta(img gocv.Mat, meta *frame.Meta) {
+	if img.Empty() {
+		return
+	}
+	now := time.Now()
+	if meta != nil {
+		now = meta.Timestamp
+	}
+
+	if reason := c.event(meta); reason != "" {
+		if c.until.IsZero() {
+			log.Printf("🎬 Event %q: recording clip (%d buffered frames)", reason, len(c.ring))
+			for _, b := range c.ring {
+				c.write(b.img, b.meta)
+				b.img.Close()
+			}
+			c.ring = nil
+		}
+		c.until = now.Add(c.post)
+	}
+
+	if !c.until.IsZero() {
+		c.write(img, meta)
+		if !now.Before(c.until) {
+			c.until = time.Time{}
+			c.rec.Close()
+		}
+		return
+	}
+
+	// No clip open: buffer the frame and forget those pre or more ago.
+	if c.pre <= 0 {
+		return
+	}
+	c.ring = append(c.ring, buffered{img: img.Clone(), meta: meta, at: now})
+	n := 0
+	for n < len(c.ring) && now.Sub(c.ring[n].at) >= c.pre {
+		c.ring[n].img.Close()
+		n++
+	}
+	c.ring = c.ring[n:]
+}
+
+// write writes a frame to the clip, unless the recorder failed less than
+// retryAfter ago. It logs when writes start failing and when they work again.
+func (c *Clipper) write(img gocv.Mat, meta *frame.Meta) {
+	now := time.Now()
+	if now.Before(c.retryAt) {
+		return
+	}
+	if err := c.rec.WriteMeta(img, meta); err != nil {
+		if c.retryAt.IsZero() {
+			log.Printf("❌ Clip recording failed, retrying every %v: %v", retryAfter, err)
+		}
+		c.retryAt = now.Add(retryAfter)
+		return
+	}
+	if !c.retryAt.IsZero() {
+		log.Printf("✅ Clip recording recovered")
+		c.retryAt = time.Time{}
+	}
+}
+
+// event returns why a clip should start or continue with this frame, or "".
+func (c *Clipper) event(meta *frame.Meta) string {
+	c.mu.Lock()
+	reason := c.triggered
+	c.triggered = ""
+	c.mu.Unlock()
+	if reason != "" {
+		return reason
+	}
+
+	if meta == nil {
+		return ""
+	}
+	for _, e := range meta.Events {
+		if len(c.events) == 0 || c.events[e] {
+			return e
+		}
+	}
+	return ""
+}
+
+// BytesWritten returns the number of clip bytes written so far.
+// Safe to call from any goroutine.
+func (c *Clipper) BytesWritten() int64 { return c.rec.BytesWritten() }
+
+// Close finishes the clip being written and frees the buffered frames.
+func (c *Clipper) Close() {
+	for _, b := range c.ring {
+		b.img.Close()
+	}
+	c.ring = nil
+	c.until = time.Time{}
+	c.rec.Close()
+}
@@ -0,0 +1,85 @@
+package recorder
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Elliot727/gocvkit/frame"
+
+	"gocv.io/x/gocv"
+)
+
+// bufferedFrames feeds frames 50ms apart to a Clipper with no event, and returns
+// the indexes it keeps for the next clip.
+func bufferedFrames(t *testing.T, pre time.Duration, frames int) []int64 {
+	c := NewClipper(filepath.Join(t.TempDir(), "clip.mp4"), pre, time.Second)
+	defer c.Close()
+
+	img := gocv.NewMatWithSize(8, 8, gocv.MatTypeCV8UC3)
+	defer img.Close()
+	start := time.Now()
+	for i := 0; i < frames; i++ {
+		meta := frame.New(int64(i))
+		meta.Timestamp = start.Add(time.Duration(i) * 50 * time.Millisecond)
+		c.WriteMeta(img, meta)
+	}
+
+	var kept []int64
+	for _, b := range c.ring {
+		kept = append(kept, b.meta.Index)
+	}
+	return kept
+}
+
+func TestClipperPreBuffer(t *testing.T) {
+	// Frames 150ms or more older than the newest one are forgotten.
+	if got := bufferedFrames(t, 150*time.Millisecond, 6); len(got) != 3 || got[0] != 3 {
+		t.Errorf("pre = 150ms kept frames %v, want [3 4 5]", got)
+	}
+	if got := bufferedFrames(t, 0, 6); len(got) != 0 {
+		t.Errorf("pre = 0 kept frames %v, want none", got)
+	}
+}
+
+// While writes are held back after a failure, frames still go to the ring
+// and events still open clips.
+func TestClipperEventsDuringBackoff(t *testing.T) {
+	c := NewClipper(filepath.Join(t.TempDir(), "clip.mp4"), time.Second, time.Second)
+	defer c.Close()
+	c.retryAt = time.Now().Add(time.Minute)
+
+	img := gocv.NewMatWithSize(8, 8, gocv.MatTypeCV8UC3)
+	defer img.Close()
+	c.WriteMeta(img, frame.New(0))
+	if len(c.ring) != 1 {
+		t.Fatalf("%d frames buffered while held back, want 1", len(c.ring))
+	}
+
+	meta := frame.New(1)
+	meta.Events = []string{"motion"}
+	c.WriteMeta(img, meta)
+	if c.until.IsZero() {
+		t.Error("an event while held back did not open a clip")
+	}
+}
+
+// With post = 0 a clip ends on the frame that triggered it.
+func TestClipperNoPost(t *testing.T) {
+	c := NewClipper(filepath.Join(t.TempDir(), "clip.mp4"), time.Second, 0)
+	defer c.Close()
+
+	img := gocv.NewMatWithSize(8, 8, gocv.MatTypeCV8UC3)
+	defer img.Close()
+	c.Trigger("test")
+	c.WriteMeta(img, frame.New(0))
+	if !c.until.IsZero() {
+		t.Fatal("the clip is still open after its event frame")
+	}
+
+	// The next frame waits in the ring for a later clip.
+	c.WriteMeta(img, frame.New(1))
+	if len(c.ring) != 1 || c.ring[0].meta.Index != 1 {
+		t.Errorf("the frame after the clip was not buffered (ring holds %d frames)", len(c.ring))
+	}
+}
@@ -0,0 +1,92 @@
+package recorder
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func touch(t *testing.T, name string, mod time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(name, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnused(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "rec-%N.mp4")
+	for _, name := range []string{"rec-0.mp4", "rec-1.mp4", "rec-3.mp4"} {
+		touch(t, filepath.Join(dir, name), time.Now())
+	}
+
+	now := time.Now()
+	if n := unused(template, 0, now); n != 2 {
+		t.Errorf("unused from 0 = %d, want 2", n)
+	}
+	if n := unused(template, 3, now); n != 4 {
+		t.Errorf("unused from 3 = %d, want 4", n)
+	}
+	if n := unused(filepath.Join(dir, "rec.mp4"), 5, now); n != 5 {
+		t.Errorf("unused without %%N = %d, want 5", n)
+	}
+}
+
+func TestExisting(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC)
+	files := []string{
+		"2024/cam-3.mp4",
+		"2024/cam-12.mp4",
+		"2024/cam-12-1.mp4", // taken name made unique by nextName
+		"2024/cam-x.mp4",    // not a number
+		"2024/cam-3.jsonl",  // a sidecar, pruned with its video
+		"2024/cam-4.mp4",    // the segment being written
+		"other.mp4",
+	}
+	for i, name := range files {
+		touch(t, filepath.Join(dir, name), base.Add(time.Duration(i)*time.Minute))
+	}
+
+	var got []string
+	for _, f := range existing(filepath.Join(dir, "%Y/cam-%N.mp4"), filepath.Join(dir, "2024/cam-4.mp4")) {
+		rel, _ := filepath.Rel(dir, f.name)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	// Oldest first.
+	want := []string{"2024/cam-3.mp4", "2024/cam-12.mp4", "2024/cam-12-1.mp4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("existing() = %q, want %q", got, want)
+	}
+}
+
+func TestNextBoundary(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800) // UTC+5:30
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 3, day, hour, min, 0, 0, ist) }
+
+	tests := []struct {
+		now      time.Time
+		interval time.Duration
+		want     time.Time
+	}{
+		{at(10, 10, 20), time.Hour, at(10, 11, 0)},
+		{at(10, 10, 20), 24 * time.Hour, at(11, 0, 0)},
+		{at(10, 10, 20), 15 * time.Minute, at(10, 10, 30)},
+		{at(10, 10, 20), 7 * time.Hour, at(10, 14, 0)},
+		{at(10, 22, 0), 7 * time.Hour, at(11, 0, 0)}, // 7h does not divide a day
+		{at(10, 11, 0), time.Hour, at(10, 12, 0)},    // on a boundary: the next one
+	}
+	for _, tt := range tests {
+		if got := nextBoundary(tt.now, tt.interval); !got.Equal(tt.want) {
+			t.Errorf("nextBoundary(%v, %v) = %v, want %v", tt.now, tt.interval, got, tt.want)
+		}
+	}
+}
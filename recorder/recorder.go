@@ -3,36 +3,115 @@
 // It handles video file creation, manages format changes during recording,
 // and provides automatic file rotation when pipeline parameters change
 // (e.g. when switching from grayscale to color or changing image dimensions).
+//
+// With a sidecar enabled, every video segment gets a JSON Lines file next to it
+// ("output-0.mp4" → "output-0.jsonl") holding one frame.Meta per written frame,
+// with its sequence number and capture time. Lines with a "type" are notes
+// about the recording rather than frames:
+//
+//	{"type":"config","index":0,...,"config":{...}}             first line: the configuration in effect
+//	{"type":"reload","index":812,...,"config":{...}}           a hot reload, taking effect with frame 812
+//	{"type":"dropped","index":96,...,"reason":"queue","frame":{...}} a frame that never reached the recording
+//
+// For round-the-clock recording, SetRotation also starts a new segment every
+// interval or size, names segments from a strftime-style template such as
+// "rec/%Y-%m-%d/%H%M%S.mp4", and deletes the oldest segments beyond a total
+// size or age. A segment is only closed right before the next frame is
+// written to its successor, so rotation never loses a frame.
+//
+// SetFormat picks the codec, container and quality; Check tries them out
+// before the first frame, since OpenCV only reports an unusable combination
+// when a file is opened.
+//
+// Clipper records short clips around events, and Snapshotter saves single
+// frames as images.
 package recorder
 
 import (
+	"encoding/json"
 	"fmt"
+	"log"
+	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/Elliot727/gocvkit/frame"
+	"github.com/Elliot727/gocvkit/internal/strftime"
 
 	"gocv.io/x/gocv"
 )
 
 // Recorder manages video recording with automatic file rotation when format changes.
 type Recorder struct {
-	writer *gocv.VideoWriter
-	fps    float64
-	fourcc string
+	writer  *gocv.VideoWriter
+	fps     float64
+	fourcc  string
+	quality int // quality is the encoder quality, 1-100 (0 = backend default)
 
 	// File naming
-	baseName string
-	ext      string
-	counter  int
+	path    string // path is the segment name template ("output-%N.mp4" when not given one)
+	counter int    // counter numbers the segments, expanding %N; numbers taken by earlier runs are skipped
+
+	// Segmenting and retention
+	rot      Rotation
+	boundary time.Time // boundary is when the current segment ends (zero = no time limit)
 
 	// Current format state
 	width    int
 	height   int
 	channels int
+
+	// Output accounting, read concurrently by BytesWritten
+	mu          sync.Mutex
+	filename    string // filename is the current segment ("" when none is open)
+	closedBytes int64  // closedBytes is the total size of finished segments
+
+	// Per-frame metadata sidecar
+	sidecar bool          // sidecar enables the JSON Lines file next to each segment
+	metaF   *os.File      // metaF is the sidecar of the current segment
+	metaEnc *json.Encoder // metaEnc writes one JSON object per line to metaF
+	config  interface{}   // config is written at the top of every sidecar
+	notes   []note        // notes wait for the next frame, guarded by mu
+}
+
+// note is a sidecar line that is not a frame's metadata.
+type note struct {
+	Type   string      `json:"type"`             // Type is "config", "reload" or "dropped"
+	Index  int64       `json:"index"`            // Index is the first frame of the segment, the first frame after a reload, or the dropped frame
+	Time   time.Time   `json:"time"`             // Time is when the note was made
+	Reason string      `json:"reason,omitempty"` // Reason is why a frame was dropped
+	Frame  *frame.Meta `json:"frame,omitempty"`  // Frame is the dropped frame's metadata
+	Config interface{} `json:"config,omitempty"` // Config is the configuration in effect from Index on
+}
+
+// Rotation controls segmenting and retention. The zero value keeps one
+// segment per frame format, forever.
+type Rotation struct {
+	Interval time.Duration // Interval starts a new segment every interval, aligned to the clock (e.g. 10m at :00, :10, ...)
+	MaxSize  int64         // MaxSize starts a new segment once the current one has reached this many bytes
+	KeepSize int64         // KeepSize deletes the oldest segments while all segments together are larger
+	KeepAge  time.Duration // KeepAge deletes segments last written longer ago than this
+}
+
+// Format is how segments are encoded.
+type Format struct {
+	Codec     string // Codec is the FOURCC of the video codec ("" keeps "mp4v")
+	Container string // Container is the file extension, replacing the one in the path ("" keeps it)
+	Quality   int    // Quality is the encoder quality, 1-100, where the backend supports it (0 = backend default)
 }
 
 // NewRecorder creates a new Recorder that writes video files to the specified path.
 // If no extension is provided, it defaults to .mp4. The recorder automatically
 // handles file rotation when the input format changes during pipeline updates.
+//
+// A path with strftime directives (%Y, %m, %d, %H, %M, %S, ...) or "%N" (the
+// segment's number) is a template expanded when each segment starts;
+// otherwise segments are numbered ("output-0.mp4", "output-1.mp4", ...).
+// Numbers whose file an earlier run left behind are skipped.
 func NewRecorder(path string) *Recorder {
 	// Split "output.mp4" into "output" and ".mp4"
 	// so we can insert numbers later: "output-1.mp4"
@@ -42,11 +121,16 @@ func NewRecorder(path string) *Recorder {
 		ext = ".mp4"
 	}
 
+	if !strftime.Has(path) && !strings.Contains(path, "%N") {
+		path = base + "-%N" + ext
+	} else if filepath.Ext(path) == "" {
+		path += ext
+	}
+
 	return &Recorder{
-		baseName: base,
-		ext:      ext,
-		fps:      30.0,
-		fourcc:   "mp4v",
+		path:   path,
+		fps:    30.0,
+		fourcc: "mp4v",
 	}
 }
 
@@ -58,25 +142,143 @@ func (r *Recorder) SetFPS(fps float64) {
 	}
 }
 
+// SetFormat sets the codec, container and quality of the next segments.
+// Call Check to find out whether this OpenCV build can write them.
+func (r *Recorder) SetFormat(f Format) {
+	if f.Codec != "" {
+		r.fourcc = f.Codec
+	}
+	if f.Container != "" {
+		r.path = strings.TrimSuffix(r.path, filepath.Ext(r.path)) + "." + f.Container
+	}
+	r.quality = f.Quality
+}
+
+// Check opens a small test file with the configured format and deletes it
+// again, so an unsupported codec, container or quality fails at startup
+// rather than on the first frame.
+func (r *Recorder) Check() error {
+	ext := filepath.Ext(r.path)
+	f, err := os.CreateTemp("", "gocvkit-check-*"+ext)
+	if err != nil {
+		return fmt.Errorf("recorder check: %w", err)
+	}
+	name := f.Name()
+	f.Close()
+	defer os.Remove(name)
+
+	w, err := r.open(name, 64, 64, true)
+	if err != nil {
+		return fmt.Errorf("cannot write codec %q to a %s file: %w", r.fourcc, ext, err)
+	}
+	w.Close()
+	return nil
+}
+
+// open opens a writer for one segment. gocv reports some failures only
+// through IsOpened, so both are checked.
+func (r *Recorder) open(name string, width, height int, isColor bool) (*gocv.VideoWriter, error) {
+	color := 0
+	if isColor {
+		color = 1
+	}
+	params := []gocv.VideoWriterProperty{gocv.VideoWriterIsColor, gocv.VideoWriterProperty(color)}
+	if r.quality > 0 {
+		params = append(params, gocv.VideoWriterQuality, gocv.VideoWriterProperty(r.quality))
+	}
+
+	w, err := gocv.VideoWriterFileWithAPIParams(name, gocv.VideoCaptureAny, r.fourcc, r.fps, width, height, params)
+	if err != nil {
+		return nil, err
+	}
+	if !w.IsOpened() {
+		w.Close()
+		if r.quality > 0 {
+			return nil, fmt.Errorf("the OpenCV backend rejected it (quality %d may be unsupported for this codec)", r.quality)
+		}
+		return nil, fmt.Errorf("the OpenCV backend rejected it")
+	}
+	return w, nil
+}
+
+// SetRotation sets when segments end and which old segments are deleted.
+// It takes effect with the next segment; retention is applied every time a
+// segment starts, to all files matching the output name (including those of
+// earlier runs).
+func (r *Recorder) SetRotation(rot Rotation) {
+	r.rot = rot
+}
+
+// SetSidecar enables or disables the per-frame JSON Lines sidecar.
+// It takes effect with the next segment.
+func (r *Recorder) SetSidecar(enabled bool) {
+	r.sidecar = enabled
+}
+
+// SetConfig sets the configuration recorded at the top of every sidecar,
+// typically the app's config.Config.
+func (r *Recorder) SetConfig(cfg interface{}) {
+	r.config = cfg
+}
+
+// NoteReload records in the sidecar that cfg took effect with frame index,
+// and makes it the configuration recorded for later segments. Call it just
+// before writing that frame.
+func (r *Recorder) NoteReload(index int64, cfg interface{}) {
+	r.config = cfg
+	r.addNote(note{Type: "reload", Index: index, Time: time.Now(), Config: cfg})
+}
+
+// NoteDropped records in the sidecar a frame that never reached the
+// recording, and why. Safe to call from any goroutine.
+func (r *Recorder) NoteDropped(meta *frame.Meta, reason string) {
+	n := note{Type: "dropped", Time: time.Now(), Reason: reason, Frame: meta}
+	if meta != nil {
+		n.Index = meta.Index
+	}
+	r.addNote(n)
+}
+
+// addNote queues n for the sidecar; it is written before the next frame,
+// in the segment that frame goes to.
+func (r *Recorder) addNote(n note) {
+	if !r.sidecar {
+		return
+	}
+	r.mu.Lock()
+	r.notes = append(r.notes, n)
+	r.mu.Unlock()
+}
+
 // Write adds the given frame to the video file.
 // The recorder automatically handles format changes by creating new files
 // when the input dimensions or channel count changes.
-func (r *Recorder) Write(frame gocv.Mat) error {
-	if frame.Empty() {
+func (r *Recorder) Write(img gocv.Mat) error {
+	return r.WriteMeta(img, nil)
+}
+
+// WriteMeta is like Write and also appends meta to the segment's sidecar,
+// if enabled. A nil meta writes only the frame.
+func (r *Recorder) WriteMeta(img gocv.Mat, meta *frame.Meta) error {
+	if img.Empty() {
 		return nil
 	}
 
-	currentCols := frame.Cols()
-	currentRows := frame.Rows()
-	currentCh := frame.Channels()
+	currentCols := img.Cols()
+	currentRows := img.Rows()
+	currentCh := img.Channels()
 
 	// CHECK: Did the format change since the last frame?
 	// If dimensions or channels changed, we MUST start a new file.
+	// The same goes for a segment that has run its time or size.
+	now := time.Now()
 	if r.writer != nil {
 		if currentCols != r.width || currentRows != r.height || currentCh != r.channels {
 			fmt.Printf("🔄 Pipeline changed (%dx%d %dc -> %dx%d %dc). Rotating video file...\n",
 				r.width, r.height, r.channels, currentCols, currentRows, currentCh)
 			r.Close() // Close the old file
+		} else if r.segmentDone(now) {
+			r.Close()
 		}
 	}
 
@@ -91,18 +293,70 @@ func (r *Recorder) Write(frame gocv.Mat) error {
 			isColor = false
 		}
 
-		// Create filename: "output-0.mp4", "output-1.mp4", etc.
-		filename := fmt.Sprintf("%s-%d%s", r.baseName, r.counter, r.ext)
+		// Create filename: "output-0.mp4", "output-1.mp4", etc. (or from the template)
+		r.counter = unused(r.path, r.counter, now)
+		filename, err := nextName(r.path, r.counter, now)
 		r.counter++
+		if err != nil {
+			return fmt.Errorf("failed to open recorder: %w", err)
+		}
 
-		w, err := gocv.VideoWriterFile(filename, r.fourcc, r.fps, r.width, r.height, isColor)
+		w, err := r.open(filename, r.width, r.height, isColor)
 		if err != nil {
 			return fmt.Errorf("failed to open recorder: %w", err)
 		}
 		r.writer = w
+
+		r.mu.Lock()
+		r.filename = filename
+		r.mu.Unlock()
+
+		r.boundary = time.Time{}
+		if r.rot.Interval > 0 {
+			r.boundary = nextBoundary(now, r.rot.Interval)
+		}
+		r.prune(filename, now)
+
+		if r.sidecar {
+			f, err := os.Create(sidecarName(filename))
+			if err != nil {
+				return fmt.Errorf("failed to open recorder sidecar: %w", err)
+			}
+			r.metaF = f
+			r.metaEnc = json.NewEncoder(f)
+
+			header := note{Type: "config", Time: now, Config: r.config}
+			if meta != nil {
+				header.Index = meta.Index
+			}
+			if err := r.metaEnc.Encode(header); err != nil {
+				return fmt.Errorf("failed to write recorder sidecar: %w", err)
+			}
+		}
+	}
+
+	if err := r.writer.Write(img); err != nil {
+		return err
 	}
 
-	return r.writer.Write(frame)
+	if r.metaEnc == nil {
+		return nil
+	}
+	r.mu.Lock()
+	notes := r.notes
+	r.notes = nil
+	r.mu.Unlock()
+	for _, n := range notes {
+		if err := r.metaEnc.Encode(n); err != nil {
+			return fmt.Errorf("failed to write recorder sidecar: %w", err)
+		}
+	}
+	if meta != nil {
+		if err := r.metaEnc.Encode(meta); err != nil {
+			return fmt.Errorf("failed to write recorder sidecar: %w", err)
+		}
+	}
+	return nil
 }
 
 // Close releases all resources used by the recorder and finalizes the video file.
@@ -111,5 +365,187 @@ func (r *Recorder) Close() {
 	if r.writer != nil {
 		r.writer.Close()
 		r.writer = nil
+
+		r.mu.Lock()
+		if fi, err := os.Stat(r.filename); err == nil {
+			r.closedBytes += fi.Size()
+		}
+		r.filename = ""
+		r.mu.Unlock()
 	}
+	if r.metaF != nil {
+		r.metaF.Close()
+		r.metaF = nil
+		r.metaEnc = nil
+	}
+}
+
+// BytesWritten returns the number of video bytes written so far across all
+// segments, including the one currently open. Safe to call from any goroutine.
+func (r *Recorder) BytesWritten() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.closedBytes
+	if r.filename != "" {
+		if fi, err := os.Stat(r.filename); err == nil {
+			n += fi.Size()
+		}
+	}
+	return n
+}
+
+// nextBoundary returns when a segment started at now ends: at the next
+// multiple of interval counted from midnight in now's time zone, so hourly
+// and daily segments split on the local hour and at local midnight, like the
+// strftime names. An interval that does not divide a day starts again from
+// the next midnight.
+func nextBoundary(now time.Time, interval time.Duration) time.Time {
+	y, m, d := now.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	b := midnight.Add(now.Sub(midnight).Truncate(interval) + interval)
+	if next := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location()); interval <= 24*time.Hour && b.After(next) {
+		b = next
+	}
+	return b
+}
+
+// segmentDone reports whether the open segment has reached its time or size limit.
+func (r *Recorder) segmentDone(now time.Time) bool {
+	if !r.boundary.IsZero() && !now.Before(r.boundary) {
+		return true
+	}
+	if r.rot.MaxSize > 0 {
+		if fi, err := os.Stat(r.filename); err == nil && fi.Size() >= r.rot.MaxSize {
+			return true
+		}
+	}
+	return false
+}
+
+// nextName names a new file by expanding template at now ("%N" is the
+// counter n). A template name that already exists gets a "-N" suffix, so no
+// file is overwritten. Missing directories are created.
+func nextName(template string, n int, now time.Time) (string, error) {
+	name := expand(template, n, now)
+
+	if strftime.Has(template) {
+		ext := filepath.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		for n := 1; ; n++ {
+			if _, err := os.Stat(name); os.IsNotExist(err) {
+				break
+			}
+			name = fmt.Sprintf("%s-%d%s", base, n, ext)
+		}
+	}
+
+	if dir := filepath.Dir(name); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+// unused returns the first number from n on whose file, expanding template
+// at now, does not exist yet. Templates without %N return n unchanged.
+func unused(template string, n int, now time.Time) int {
+	if !strings.Contains(template, "%N") {
+		return n
+	}
+	for {
+		if _, err := os.Stat(expand(template, n, now)); os.IsNotExist(err) {
+			return n
+		}
+		n++
+	}
+}
+
+// expand expands template at now, with n for "%N".
+func expand(template string, n int, now time.Time) string {
+	return strings.ReplaceAll(strftime.Format(template, now), "%N", fmt.Sprint(n))
+}
+
+// prune applies the retention limits to every file the output name can
+// produce, except current: segments older than KeepAge are deleted, then the
+// oldest ones while the total is above KeepSize. Sidecars go with their video.
+func (r *Recorder) prune(current string, now time.Time) {
+	if r.rot.KeepSize <= 0 && r.rot.KeepAge <= 0 {
+		return
+	}
+
+	segs := existing(r.path, current)
+	var total int64
+	for _, s := range segs {
+		total += s.size
+	}
+
+	for _, s := range segs {
+		tooOld := r.rot.KeepAge > 0 && now.Sub(s.mod) > r.rot.KeepAge
+		tooBig := r.rot.KeepSize > 0 && total > r.rot.KeepSize
+		if !tooOld && !tooBig {
+			break
+		}
+		if err := os.Remove(s.name); err != nil {
+			log.Printf("⚠️  Recorder retention: %v", err)
+			continue
+		}
+		os.Remove(sidecarName(s.name))
+		if dir := filepath.Dir(s.name); dir != filepath.Dir(current) {
+			os.Remove(dir) // a dated folder goes once empty; fails harmlessly otherwise
+		}
+		total -= s.size
+		log.Printf("🗑️  Deleted old segment %s", s.name)
+	}
+}
+
+// file is an existing file produced by an output template.
+type file struct {
+	name string
+	size int64
+	mod  time.Time
+}
+
+// existing lists the files template can produce ("%N" standing for any
+// number, and with the "-N" suffix of nextName), except current, oldest first.
+func existing(template, current string) []file {
+	// strftime leaves %N alone, so the pieces around it are expanded on their
+	// own and joined by the number.
+	ext := filepath.Ext(template)
+	if strings.Contains(ext, "%") {
+		ext = ""
+	}
+	pieces := strings.Split(strings.TrimSuffix(template, ext), "%N")
+	globs := make([]string, len(pieces))
+	patterns := make([]string, len(pieces))
+	for i, piece := range pieces {
+		globs[i] = strftime.Glob(piece)
+		patterns[i] = strftime.Pattern(piece)
+	}
+	candidates, err := filepath.Glob(strings.Join(globs, "*") + strftime.Glob(ext))
+	if err != nil {
+		return nil
+	}
+	// A name taken when a segment started gets a "-N" suffix (see nextName).
+	match := regexp.MustCompile("^" + strings.Join(patterns, `\d+`) + `(-\d+)?` + regexp.QuoteMeta(ext) + "$")
+
+	var files []file
+	for _, name := range candidates {
+		if name == current || !match.MatchString(name) {
+			continue
+		}
+		fi, err := os.Stat(name)
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		files = append(files, file{name, fi.Size(), fi.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.Before(files[j].mod) })
+	return files
+}
+
+// sidecarName returns the JSON Lines file that goes with a segment.
+func sidecarName(segment string) string {
+	return strings.TrimSuffix(segment, filepath.Ext(segment)) + ".jsonl"
 }
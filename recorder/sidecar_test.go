@@ -0,0 +1,93 @@
+package recorder
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Elliot727/gocvkit/frame"
+
+	"gocv.io/x/gocv"
+)
+
+// sidecarLine holds the fields of a sidecar line the tests look at.
+type sidecarLine struct {
+	Type   string         `json:"type"`
+	Index  int64          `json:"index"`
+	Reason string         `json:"reason"`
+	Config map[string]int `json:"config"`
+}
+
+// readSidecar returns the lines of the only sidecar in dir.
+func readSidecar(t *testing.T, dir string) []sidecarLine {
+	t.Helper()
+	names, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if len(names) != 1 {
+		t.Fatalf("sidecars %v, want 1", names)
+	}
+	f, err := os.Open(names[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var lines []sidecarLine
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var l sidecarLine
+		if err := json.Unmarshal(sc.Bytes(), &l); err != nil {
+			t.Fatalf("sidecar line %q: %v", sc.Text(), err)
+		}
+		lines = append(lines, l)
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return lines
+}
+
+func TestSidecar(t *testing.T) {
+	dir := t.TempDir()
+	r := NewRecorder(filepath.Join(dir, "out.mp4"))
+	r.SetSidecar(true)
+	r.SetConfig(map[string]int{"version": 1})
+
+	img := gocv.NewMatWithSize(48, 64, gocv.MatTypeCV8UC3)
+	defer img.Close()
+	write := func(index int64) {
+		t.Helper()
+		if err := r.WriteMeta(img, frame.New(index)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(0)
+	write(1)
+	r.NoteReload(2, map[string]int{"version": 2})
+	write(2)
+	r.NoteDropped(frame.New(3), "queue")
+	write(4)
+	r.Close()
+
+	want := []sidecarLine{
+		{Type: "config", Index: 0, Config: map[string]int{"version": 1}},
+		{Index: 0},
+		{Index: 1},
+		{Type: "reload", Index: 2, Config: map[string]int{"version": 2}},
+		{Index: 2},
+		{Type: "dropped", Index: 3, Reason: "queue"},
+		{Index: 4},
+	}
+	got := readSidecar(t, dir)
+	if len(got) != len(want) {
+		t.Fatalf("sidecar has %d lines, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Type != w.Type || g.Index != w.Index || g.Reason != w.Reason || g.Config["version"] != w.Config["version"] {
+			t.Errorf("line %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package recorder
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Elliot727/gocvkit/frame"
+	"github.com/Elliot727/gocvkit/internal/strftime"
+
+	"gocv.io/x/gocv"
+)
+
+// Snapshotter saves single output frames as image files: every interval,
+// and on demand with Save. The image format follows the path's extension:
+// ".png", ".jpg"/".jpeg" or ".webp".
+type Snapshotter struct {
+	path     string        // path is the file name template ("snapshot-%N.png" when not given one)
+	interval time.Duration // interval saves a frame every interval, aligned to the clock (0 = on demand only)
+	quality  int           // quality is the JPEG/WebP quality, 1-100 (0 = encoder default)
+	maxFiles int           // maxFiles deletes the oldest snapshots beyond this many (0 = keep all)
+
+	mu      sync.Mutex
+	last    gocv.Mat  // last is a copy of the latest frame, saved by Save
+	lastAt  time.Time // lastAt is when last was captured
+	counter int       // counter numbers the snapshots, expanding %N; numbers taken by earlier runs are skipped
+	due     time.Time // due is when the next interval snapshot is taken
+}
+
+// NewSnapshotter creates a Snapshotter writing to path. Like a Recorder's
+// output, path may be a strftime template; "%N" expands to the snapshot's
+// sequence number, which a path without directives gets appended
+// ("snapshot.png" → "snapshot-0.png", "snapshot-1.png", ...). Without an
+// extension the images are PNG.
+func NewSnapshotter(path string) *Snapshotter {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		ext = ".png"
+		path += ext
+	}
+	if !strftime.Has(path) && !strings.Contains(path, "%N") {
+		path = strings.TrimSuffix(path, ext) + "-%N" + ext
+	}
+
+	return &Snapshotter{
+		path: path,
+		last: gocv.NewMat(),
+	}
+}
+
+// SetInterval saves a frame every interval, aligned to the clock; 0 saves
+// only on demand.
+func (s *Snapshotter) SetInterval(interval time.Duration) {
+	s.mu.Lock()
+	s.interval = interval
+	s.due = time.Time{}
+	s.mu.Unlock()
+}
+
+// SetQuality sets the JPEG or WebP quality (1-100; 0 = encoder default).
+func (s *Snapshotter) SetQuality(quality int) {
+	s.mu.Lock()
+	s.quality = quality
+	s.mu.Unlock()
+}
+
+// SetMaxFiles keeps at most n snapshots, counting those of earlier runs,
+// deleting the oldest first; 0 keeps all.
+func (s *Snapshotter) SetMaxFiles(n int) {
+	s.mu.Lock()
+	s.maxFiles = n
+	s.mu.Unlock()
+}
+
+// WriteMeta feeds the next output frame, saving it when an interval
+// snapshot is due. img is copied, so the caller keeps ownership.
+func (s *Snapshotter) WriteMeta(img gocv.Mat, meta *frame.Meta) error {
+	if img.Empty() {
+		return nil
+	}
+	at := time.Now()
+	if meta != nil {
+		at = meta.Timestamp
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	img.CopyTo(&s.last)
+	s.lastAt = at
+
+	if s.interval <= 0 || at.Before(s.due) {
+		return nil
+	}
+	s.due = at.Truncate(s.interval).Add(s.interval)
+	_, err := s.save()
+	return err
+}
+
+// Save writes the latest frame to a new snapshot file and returns its name.
+// Safe to call from any goroutine.
+func (s *Snapshotter) Save() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save()
+}
+
+// save writes s.last; s.mu must be held.
+func (s *Snapshotter) save() (string, error) {
+	if s.last.Empty() {
+		return "", fmt.Errorf("no frame processed yet")
+	}
+
+	// Numbers taken by an earlier run are skipped, so a restart does not
+	// overwrite its snapshots.
+	s.counter = unused(s.path, s.counter, s.lastAt)
+	name, err := nextName(s.path, s.counter, s.lastAt)
+	if err != nil {
+		return "", fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	s.counter++
+
+	var params []int
+	if s.quality > 0 {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".jpg", ".jpeg":
+			params = []int{gocv.IMWriteJpegQuality, s.quality}
+		case ".webp":
+			params = []int{gocv.IMWriteWebpQuality, s.quality}
+		}
+	}
+	if !gocv.IMWriteWithParams(name, s.last, params) {
+		return "", fmt.Errorf("failed to save snapshot %s", name)
+	}
+	log.Printf("📸 Saved snapshot %s", name)
+
+	if s.maxFiles > 0 {
+		old := existing(s.path, name)
+		for len(old) >= s.maxFiles {
+			if err := os.Remove(old[0].name); err != nil {
+				log.Printf("⚠️  Snapshot retention: %v", err)
+			}
+			old = old[1:]
+		}
+	}
+	return name, nil
+}
+
+// Close frees the latest frame.
+func (s *Snapshotter) Close() {
+	s.mu.Lock()
+	s.last.Close()
+	s.mu.Unlock()
+}
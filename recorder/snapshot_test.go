@@ -0,0 +1,49 @@
+package recorder
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Elliot727/gocvkit/frame"
+
+	"gocv.io/x/gocv"
+)
+
+func TestSnapshotterSave(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, filepath.Join(dir, "snap-0.png"), time.Now().Add(-time.Hour)) // left by an earlier run
+
+	s := NewSnapshotter(filepath.Join(dir, "snap.png"))
+	defer s.Close()
+	s.SetMaxFiles(2)
+
+	if _, err := s.Save(); err == nil {
+		t.Error("Save() succeeded before any frame")
+	}
+
+	img := gocv.NewMatWithSize(8, 8, gocv.MatTypeCV8UC3)
+	defer img.Close()
+	if err := s.WriteMeta(img, frame.New(0)); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"snap-1.png", "snap-2.png"} {
+		name, err := s.Save()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(name) != want {
+			t.Errorf("Save() = %s, want %s", filepath.Base(name), want)
+		}
+	}
+
+	// max_files = 2 removed the oldest snapshot.
+	left, _ := filepath.Glob(filepath.Join(dir, "*.png"))
+	for i := range left {
+		left[i] = filepath.Base(left[i])
+	}
+	if !slices.Equal(left, []string{"snap-1.png", "snap-2.png"}) {
+		t.Errorf("files left: %q", left)
+	}
+}
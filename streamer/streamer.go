@@ -4,14 +4,20 @@
 // It implements the http.Handler interface to serve streams to multiple clients simultaneously.
 // The streamer handles concurrent client connections, frame broadcasting, and rate limiting
 // to maintain optimal performance.
+//
+// Alongside the pixels, the streamer keeps the metadata of the latest frame
+// and serves it as JSON through MetaHandler.
 package streamer
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/Elliot727/gocvkit/frame"
+
 	"gocv.io/x/gocv"
 )
 
@@ -23,6 +29,7 @@ type MJPEGStreamer struct {
 	latestFrame []byte                   // latestFrame keeps the most recently encoded frame
 	lastSent    time.Time                // lastSent tracks the time of the last frame broadcast
 	interval    time.Duration            // interval sets the minimum time between consecutive broadcasts
+	latestMeta  *frame.Meta              // latestMeta is the metadata of the most recently published frame
 }
 
 // NewMJPEGStreamer creates and initializes a new MJPEG streamer instance.
@@ -129,3 +136,37 @@ func (s *MJPEGStreamer) Broadcast(frame gocv.Mat, quality int) {
 	}
 	s.mu.Unlock()
 }
+
+// Clients returns the number of currently connected stream viewers.
+func (s *MJPEGStreamer) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// PublishMeta makes meta the latest frame metadata served by MetaHandler.
+// The caller must not modify meta afterwards.
+func (s *MJPEGStreamer) PublishMeta(meta *frame.Meta) {
+	s.mu.Lock()
+	s.latestMeta = meta
+	s.mu.Unlock()
+}
+
+// MetaHandler returns an http.Handler that responds with the latest frame
+// metadata as JSON (or 204 No Content before the first frame).
+func (s *MJPEGStreamer) MetaHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		meta := s.latestMeta
+		s.mu.Unlock()
+
+		if meta == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		json.NewEncoder(w).Encode(meta)
+	})
+}